@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Adam Yarborough @littletoyrobots
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/littletoyrobots/nsgraphgen/internal/graphgen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "List certificates, their expiry and the vservers they protect",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := viper.GetString("input-file")
+		ignoreNames := viper.GetStringSlice("ignore-name")
+		ignoreTypes := viper.GetStringSlice("ignore-type")
+		certDir := viper.GetString("cert-dir")
+		expiryDays := viper.GetInt("cert-expiry-days")
+		format := viper.GetString("format")
+
+		ns := graphgen.New("", ignoreNames, ignoreTypes, []string{})
+		if err := ns.Parse(inputFile); err != nil {
+			log.Fatal(err)
+		}
+		if certDir != "" {
+			ns.LoadCerts(certDir, expiryDays)
+		}
+		certs := ns.Certs()
+
+		switch format {
+		case "text":
+			return graphgen.WriteCertsTable(os.Stdout, certs)
+		case "json":
+			return graphgen.WriteCertsJSON(os.Stdout, certs)
+		}
+		return fmt.Errorf("invalid format: %v. \nvalue must be in [text json]", format)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(certsCmd)
+
+	certsCmd.Flags().String("format", "text", "output format: text or json")
+}
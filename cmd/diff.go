@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Adam Yarborough @littletoyrobots
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/littletoyrobots/nsgraphgen/internal/graphgen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two configs and graph the added, removed and changed resources",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rankdir := viper.GetString("rankdir")
+		oldFile := viper.GetString("old-file")
+		newFile := viper.GetString("new-file")
+		outputFile := viper.GetString("output-file")
+		ignoreNames := viper.GetStringSlice("ignore-name")
+		ignoreTypes := graphIgnoreTypes()
+		isolateNames := viper.GetStringSlice("isolate-name")
+		stdout := viper.GetBool("stdout")
+		expandPatsets := viper.GetBool("expand-patsets")
+		graph := viper.GetString("graph")
+
+		if oldFile == "" || newFile == "" {
+			return fmt.Errorf("both --old-file and --new-file are required")
+		}
+		if graph != "" && graph != "dot" && graph != "mermaid" {
+			return fmt.Errorf("invalid graph: %v. \nvalue must be in [dot mermaid]", graph)
+		}
+
+		a := graphgen.New(rankdir, ignoreNames, ignoreTypes, isolateNames)
+		a.ExpandPatsets = expandPatsets
+		if err := a.Parse(oldFile); err != nil {
+			log.Fatal(err)
+		}
+		b := graphgen.New(rankdir, ignoreNames, ignoreTypes, isolateNames)
+		b.ExpandPatsets = expandPatsets
+		if err := b.Parse(newFile); err != nil {
+			log.Fatal(err)
+		}
+		merged, changes := graphgen.Diff(a, b)
+
+		if graph == "" || !stdout {
+			for _, c := range changes {
+				fmt.Println(c)
+			}
+			if len(changes) < 1 {
+				fmt.Println("no changes found")
+			}
+		}
+
+		switch graph {
+		case "dot":
+			merged.ExportDot(outputFile, stdout)
+		case "mermaid":
+			merged.ExportMermaid(outputFile, stdout)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringP("old-file", "a", "", "original netscaler config file")
+	diffCmd.Flags().StringP("new-file", "b", "", "updated netscaler config file")
+	diffCmd.Flags().String("graph", "dot", "render the merged changes as a dot or mermaid graph, empty for report only")
+}
@@ -24,14 +24,25 @@ var dotCmd = &cobra.Command{
 		inputFile := viper.GetString("input-file")
 		outputFile := viper.GetString("output-file")
 		ignoreNames := viper.GetStringSlice("ignore-name")
-		ignoreTypes := viper.GetStringSlice("ignore-type")
+		ignoreTypes := graphIgnoreTypes()
 		isolateNames := viper.GetStringSlice("isolate-name")
 		stdout := viper.GetBool("stdout")
+		expandPatsets := viper.GetBool("expand-patsets")
+		onlyOrphans := viper.GetBool("only-orphans")
+		certDir := viper.GetString("cert-dir")
+		expiryDays := viper.GetInt("cert-expiry-days")
 
 		ns := graphgen.New(rankdir, ignoreNames, ignoreTypes, isolateNames)
+		ns.ExpandPatsets = expandPatsets
 		if err := ns.Parse(inputFile); err != nil {
 			log.Fatal(err)
 		}
+		if certDir != "" {
+			ns.LoadCerts(certDir, expiryDays)
+		}
+		if onlyOrphans {
+			ns.KeepOrphans()
+		}
 		ns.ExportDot(outputFile, stdout)
 		return nil
 	},
@@ -44,6 +55,8 @@ func init() {
 
 	rootCmd.AddCommand(dotCmd)
 
+	dotCmd.Flags().Bool("only-orphans", false, "only graph objects not referenced from any vserver")
+
 	// dotCmd.Flags().Bool("dark-mode", false, "set output to dark mode")
 	// dotCmd.Flags().Bool("include-legend", false, "include legend / key")
 
@@ -0,0 +1,77 @@
+/*
+Copyright © 2025 Adam Yarborough @littletoyrobots
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/littletoyrobots/nsgraphgen/internal/graphgen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// impactCmd represents the impact command
+var impactCmd = &cobra.Command{
+	Use:   "impact",
+	Short: "Show the VIPs, domains and vservers that depend on a resource",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rankdir := viper.GetString("rankdir")
+		inputFile := viper.GetString("input-file")
+		outputFile := viper.GetString("output-file")
+		ignoreNames := viper.GetStringSlice("ignore-name")
+		ignoreTypes := viper.GetStringSlice("ignore-type")
+		stdout := viper.GetBool("stdout")
+		expandPatsets := viper.GetBool("expand-patsets")
+		targets := viper.GetStringSlice("target")
+		graph := viper.GetString("graph")
+
+		if len(targets) < 1 {
+			return fmt.Errorf("at least one --target is required")
+		}
+		if graph != "" && graph != "dot" && graph != "mermaid" {
+			return fmt.Errorf("invalid graph: %v. \nvalue must be in [dot mermaid]", graph)
+		}
+
+		ns := graphgen.New(rankdir, ignoreNames, ignoreTypes, []string{})
+		ns.ExpandPatsets = expandPatsets
+		if err := ns.Parse(inputFile); err != nil {
+			log.Fatal(err)
+		}
+		routes := ns.Impact(targets)
+
+		if graph == "" || !stdout {
+			target := ""
+			for _, r := range routes {
+				if r.Target != target {
+					target = r.Target
+					fmt.Printf("%s\n", target)
+				}
+				fmt.Printf("  %-12s %s\n", r.EntryType, r.Entry)
+				fmt.Printf("  %-12s %s\n", "", strings.Join(r.Route, " -> "))
+			}
+			if len(routes) < 1 {
+				fmt.Println("no affected VIPs, domains or vservers found")
+			}
+		}
+
+		switch graph {
+		case "dot":
+			ns.HighlightImpact(routes)
+			ns.ExportDot(outputFile, stdout)
+		case "mermaid":
+			ns.HighlightImpact(routes)
+			ns.ExportMermaid(outputFile, stdout)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(impactCmd)
+
+	impactCmd.Flags().StringSliceP("target", "t", []string{}, "names or IPs of servers, services or certs to analyse")
+	impactCmd.Flags().String("graph", "", "also render the impacted routes as a dot or mermaid graph")
+}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Adam Yarborough @littletoyrobots
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/littletoyrobots/nsgraphgen/internal/graphgen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Audit a config for unused, unbound and misconfigured resources",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := viper.GetString("input-file")
+		ignoreNames := viper.GetStringSlice("ignore-name")
+		ignoreTypes := viper.GetStringSlice("ignore-type")
+		enableRules := viper.GetStringSlice("enable-rule")
+		disableRules := viper.GetStringSlice("disable-rule")
+		format := viper.GetString("format")
+
+		if !slices.Contains([]string{"text", "json", "sarif"}, format) {
+			return fmt.Errorf("invalid format: %v. \nvalue must be in [text json sarif]", format)
+		}
+		for _, each := range append(enableRules, disableRules...) {
+			if !slices.Contains(graphgen.LintRuleIDs(), each) {
+				return fmt.Errorf("invalid lint rule: %v. \nvalue must be in %v", each, graphgen.LintRuleIDs())
+			}
+		}
+
+		ns := graphgen.New("", ignoreNames, ignoreTypes, []string{})
+		if err := ns.Parse(inputFile); err != nil {
+			log.Fatal(err)
+		}
+		findings := ns.Lint(enableRules, disableRules)
+
+		switch format {
+		case "text":
+			return graphgen.WriteLintText(os.Stdout, inputFile, findings)
+		case "json":
+			return graphgen.WriteLintJSON(os.Stdout, findings)
+		case "sarif":
+			return graphgen.WriteLintSARIF(os.Stdout, inputFile, findings)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().String("format", "text", "output format: text, json or sarif")
+	lintCmd.Flags().StringSlice("enable-rule", []string{}, "only run the named lint rules")
+	lintCmd.Flags().StringSlice("disable-rule", []string{}, "names of lint rules to skip")
+}
@@ -19,12 +19,23 @@ var mermaidCmd = &cobra.Command{
 		inputFile := viper.GetString("input-file")
 		outputFile := viper.GetString("output-file")
 		ignoreNames := viper.GetStringSlice("ignore-name")
-		ignoreTypes := viper.GetStringSlice("ignore-type")
+		ignoreTypes := graphIgnoreTypes()
 		isolateNames := viper.GetStringSlice("isolate-name")
 		stdout := viper.GetBool("stdout")
+		expandPatsets := viper.GetBool("expand-patsets")
+		onlyOrphans := viper.GetBool("only-orphans")
+		certDir := viper.GetString("cert-dir")
+		expiryDays := viper.GetInt("cert-expiry-days")
 
 		ns := graphgen.New(rankdir, ignoreNames, ignoreTypes, isolateNames)
+		ns.ExpandPatsets = expandPatsets
 		ns.Parse(inputFile)
+		if certDir != "" {
+			ns.LoadCerts(certDir, expiryDays)
+		}
+		if onlyOrphans {
+			ns.KeepOrphans()
+		}
 		ns.ExportMermaid(outputFile, stdout)
 		return nil
 	},
@@ -34,6 +45,8 @@ func init() {
 	// mermaidCmd.SilenceUsage = true
 	rootCmd.AddCommand(mermaidCmd)
 
+	mermaidCmd.Flags().Bool("only-orphans", false, "only graph objects not referenced from any vserver")
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Adam Yarborough @littletoyrobots
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/littletoyrobots/nsgraphgen/internal/graphgen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// nfactorCmd represents the nfactor command
+var nfactorCmd = &cobra.Command{
+	Use:   "nfactor",
+	Short: "Graph the nFactor authentication flow of each AAA vserver",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := viper.GetString("input-file")
+		outputFile := viper.GetString("output-file")
+		ignoreNames := viper.GetStringSlice("ignore-name")
+		ignoreTypes := graphIgnoreTypes()
+		stdout := viper.GetBool("stdout")
+		format := viper.GetString("format")
+		vservers := viper.GetStringSlice("vserver")
+
+		if format != "dot" && format != "mermaid" {
+			return fmt.Errorf("invalid format: %v. \nvalue must be in [dot mermaid]", format)
+		}
+
+		ns := graphgen.New("LR", ignoreNames, ignoreTypes, []string{})
+		if err := ns.Parse(inputFile); err != nil {
+			log.Fatal(err)
+		}
+		flows := []graphgen.NFactorFlow{}
+		for _, f := range ns.NFactorFlows() {
+			if len(vservers) < 1 || slices.Contains(vservers, f.VServer) {
+				flows = append(flows, f)
+			}
+		}
+		if len(flows) < 1 {
+			return fmt.Errorf("no authentication vservers found")
+		}
+
+		for _, f := range flows {
+			// one file per vserver, e.g. graph-aaa-vs.out
+			out := outputFile
+			if len(flows) > 1 {
+				ext := filepath.Ext(outputFile)
+				out = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(outputFile, ext), f.VServer, ext)
+			}
+			switch format {
+			case "dot":
+				f.Graph.ExportDot(out, stdout)
+			case "mermaid":
+				f.Graph.ExportMermaid(out, stdout)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nfactorCmd)
+
+	nfactorCmd.Flags().String("format", "dot", "output format: dot or mermaid")
+	nfactorCmd.Flags().StringSlice("vserver", []string{}, "names of authentication vservers to graph, default all")
+}
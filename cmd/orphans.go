@@ -0,0 +1,52 @@
+/*
+Copyright © 2025 Adam Yarborough @littletoyrobots
+*/
+package cmd
+
+import (
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/littletoyrobots/nsgraphgen/internal/graphgen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// orphansCmd represents the orphans command
+var orphansCmd = &cobra.Command{
+	Use:   "orphans",
+	Short: "List objects not referenced from any vserver, with a cleanup script",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := viper.GetString("input-file")
+		ignoreNames := viper.GetStringSlice("ignore-name")
+		ignoreTypes := viper.GetStringSlice("ignore-type")
+		scriptFile := viper.GetString("script")
+
+		ns := graphgen.New("", ignoreNames, ignoreTypes, []string{})
+		if err := ns.Parse(inputFile); err != nil {
+			log.Fatal(err)
+		}
+		orphans := ns.Orphans()
+		if err := graphgen.WriteOrphanReport(os.Stdout, orphans); err != nil {
+			return err
+		}
+
+		if scriptFile != "" {
+			slog.Info("generating cleanup script", "script", scriptFile)
+			f, err := os.Create(scriptFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return graphgen.WriteOrphanScript(f, inputFile, orphans)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(orphansCmd)
+
+	orphansCmd.Flags().String("script", "", "write the rm commands to remove orphans to this file")
+}
@@ -58,6 +58,10 @@ func init() {
 	rootCmd.PersistentFlags().StringSlice("ignore-type", []string{}, "names of types to ignore from graphs")
 	rootCmd.PersistentFlags().StringSlice("isolate-name", []string{}, "names of resources to isolate in graph")
 	rootCmd.PersistentFlags().Bool("stdout", false, "output to STDOUT, overrides output-file")
+	rootCmd.PersistentFlags().Bool("show-monitors", false, "include load balancing monitors in graphs")
+	rootCmd.PersistentFlags().Bool("expand-patsets", false, "draw patset entries as domain names in graphs")
+	rootCmd.PersistentFlags().String("cert-dir", "", "local copy of /nsconfig/ssl to read certificate details from")
+	rootCmd.PersistentFlags().Int("cert-expiry-days", 30, "highlight certificates expiring within this many days")
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "nsgraphgen config file (default: ./config.yaml)")
 	rootCmd.PersistentFlags().BoolP("verbose", "V", false, "enable verbose output")
@@ -69,6 +73,17 @@ func init() {
 	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
+// graphIgnoreTypes returns the types to leave out of rendered graphs.
+// Monitors are bound to nearly every service, so they are hidden unless
+// show-monitors is set.
+func graphIgnoreTypes() []string {
+	ignoreTypes := viper.GetStringSlice("ignore-type")
+	if !viper.GetBool("show-monitors") && !slices.Contains(ignoreTypes, "Monitor") {
+		ignoreTypes = append(ignoreTypes, "Monitor")
+	}
+	return ignoreTypes
+}
+
 func initializeConfig(cmd *cobra.Command) error {
 	// cmd.SilenceUsage = true
 	// setup viper to use environmental variables
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestGraphIgnoreTypes(t *testing.T) {
+	tests := []struct {
+		showMonitors bool
+		ignoreTypes  []string
+		want         []string
+	}{
+		{false, []string{}, []string{"Monitor"}},
+		{false, []string{"Cert"}, []string{"Cert", "Monitor"}},
+		{false, []string{"Monitor"}, []string{"Monitor"}},
+		{true, []string{}, []string{}},
+		{true, []string{"Cert"}, []string{"Cert"}},
+	}
+	for _, tt := range tests {
+		viper.Reset()
+		viper.Set("show-monitors", tt.showMonitors)
+		viper.Set("ignore-type", tt.ignoreTypes)
+		if got := graphIgnoreTypes(); !slices.Equal(got, tt.want) {
+			t.Errorf("graphIgnoreTypes() with show-monitors=%v and ignore-type=%v = %v, want %v", tt.showMonitors, tt.ignoreTypes, got, tt.want)
+		}
+	}
+	viper.Reset()
+}
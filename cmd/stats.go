@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Adam Yarborough @littletoyrobots
+*/
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/littletoyrobots/nsgraphgen/internal/graphgen"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarise resource counts, protocols and graph shape of a config",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		inputFile := viper.GetString("input-file")
+		ignoreNames := viper.GetStringSlice("ignore-name")
+		ignoreTypes := viper.GetStringSlice("ignore-type")
+		format := viper.GetString("format")
+
+		ns := graphgen.New("", ignoreNames, ignoreTypes, []string{})
+		if err := ns.Parse(inputFile); err != nil {
+			log.Fatal(err)
+		}
+		st := ns.Stats()
+
+		switch format {
+		case "text":
+			return graphgen.WriteStatsTable(os.Stdout, st)
+		case "json":
+			return graphgen.WriteStatsJSON(os.Stdout, st)
+		}
+		return fmt.Errorf("invalid format: %v. \nvalue must be in [text json]", format)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().String("format", "text", "output format: text or json")
+}
@@ -0,0 +1,205 @@
+package graphgen
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// nsSSLDir is where the appliance stores certificate files referenced by
+// relative paths in add ssl certkey.
+var nsSSLDir = "/nsconfig/ssl"
+
+var certColors = map[string]string{
+	"expired":  "red",
+	"expiring": "orange",
+}
+
+// CertInfo describes a certificate and the vservers it protects.
+type CertInfo struct {
+	Name     string    `json:"name"`
+	NSType   string    `json:"nstype"`
+	CertFile string    `json:"certFile,omitempty"`
+	KeyFile  string    `json:"keyFile,omitempty"`
+	Subject  string    `json:"subject,omitempty"`
+	Issuer   string    `json:"issuer,omitempty"`
+	SANs     []string  `json:"sans,omitempty"`
+	NotAfter time.Time `json:"notAfter,omitzero"`
+	DaysLeft int       `json:"daysLeft"`
+	VServers []string  `json:"vservers"`
+}
+
+func readCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+		data = rest
+	}
+	// not PEM, try DER
+	return x509.ParseCertificate(data)
+}
+
+// resolveCertFile maps a certkey -cert path onto a local copy of
+// /nsconfig/ssl.
+func resolveCertFile(certDir, certFile string) string {
+	if strings.HasPrefix(certFile, nsSSLDir+"/") {
+		certFile = strings.TrimPrefix(certFile, nsSSLDir+"/")
+	}
+	path := filepath.Join(certDir, certFile)
+	if _, err := os.Stat(path); err != nil {
+		return filepath.Join(certDir, filepath.Base(certFile))
+	}
+	return path
+}
+
+// LoadCerts reads each certkey's certificate file from certDir, recording its
+// subject, SANs, issuer and expiry, and colours certs that have expired or
+// expire within expiryDays.
+func (ns *NSGraph) LoadCerts(certDir string, expiryDays int) {
+	slog.Info("loading certificates", "cert-dir", certDir)
+	now := time.Now()
+	for i, n := range ns.Nodes {
+		if n.nstype != "Cert" && n.nstype != "CACert" {
+			continue
+		}
+		certFile := n.attrs["certFile"]
+		if certFile == "" {
+			continue
+		}
+		path := resolveCertFile(certDir, certFile)
+		cert, err := readCertificate(path)
+		if err != nil {
+			slog.Warn("could not read certificate", "name", n.name, "path", path, "error", err)
+			continue
+		}
+		ns.setNodeAttr(n.label, "subject", cert.Subject.String())
+		ns.setNodeAttr(n.label, "issuer", cert.Issuer.String())
+		ns.setNodeAttr(n.label, "sans", strings.Join(cert.DNSNames, ","))
+		ns.setNodeAttr(n.label, "notAfter", cert.NotAfter.UTC().Format(time.RFC3339))
+
+		switch {
+		case now.After(cert.NotAfter):
+			ns.Nodes[i].color = certColors["expired"]
+			slog.Warn("certificate expired", "name", n.name, "notAfter", cert.NotAfter)
+		case now.AddDate(0, 0, expiryDays).After(cert.NotAfter):
+			ns.Nodes[i].color = certColors["expiring"]
+			slog.Info("certificate expiring", "name", n.name, "notAfter", cert.NotAfter)
+		}
+	}
+}
+
+// protectedVServers walks upstream from a cert, through any certs chained to
+// it, to the vservers it is bound to.
+func (ns *NSGraph) protectedVServers(label string) []string {
+	vservers := []string{}
+	seen := map[string]bool{label: true}
+	queue := []string{label}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range ns.getEdgesTo(current) {
+			from := ns.getNodeByLabel(e.from)
+			if from == nil || seen[from.label] {
+				continue
+			}
+			seen[from.label] = true
+			switch {
+			case strings.HasSuffix(from.nstype, "VServer"):
+				vservers = append(vservers, from.label)
+			case from.nstype == "Cert" || from.nstype == "CACert":
+				queue = append(queue, from.label)
+			}
+		}
+	}
+	slices.Sort(vservers)
+	return vservers
+}
+
+// Certs lists every certificate, soonest to expire first.
+func (ns *NSGraph) Certs() []CertInfo {
+	certs := []CertInfo{}
+	now := time.Now()
+	for _, n := range ns.Nodes {
+		if n.nstype != "Cert" && n.nstype != "CACert" {
+			continue
+		}
+		c := CertInfo{
+			Name:     n.label,
+			NSType:   n.nstype,
+			CertFile: n.attrs["certFile"],
+			KeyFile:  n.attrs["keyFile"],
+			Subject:  n.attrs["subject"],
+			Issuer:   n.attrs["issuer"],
+			VServers: ns.protectedVServers(n.label),
+		}
+		if n.attrs["sans"] != "" {
+			c.SANs = strings.Split(n.attrs["sans"], ",")
+		}
+		if t, err := time.Parse(time.RFC3339, n.attrs["notAfter"]); err == nil {
+			c.NotAfter = t
+			c.DaysLeft = int(t.Sub(now).Hours() / 24)
+		}
+		certs = append(certs, c)
+	}
+
+	slices.SortStableFunc(certs, func(a, b CertInfo) int {
+		// certs without a known expiry sort last
+		if a.NotAfter.IsZero() != b.NotAfter.IsZero() {
+			if a.NotAfter.IsZero() {
+				return 1
+			}
+			return -1
+		}
+		if c := a.NotAfter.Compare(b.NotAfter); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return certs
+}
+
+// WriteCertsTable writes the certificate inventory as a plain text table.
+func WriteCertsTable(w io.Writer, certs []CertInfo) error {
+	if _, err := fmt.Fprintf(w, "%-32s %-10s %6s  %-40s %s\n", "NAME", "EXPIRES", "DAYS", "SUBJECT", "VSERVERS"); err != nil {
+		return err
+	}
+	for _, c := range certs {
+		expires, days := "-", "-"
+		if !c.NotAfter.IsZero() {
+			expires = c.NotAfter.Format(time.DateOnly)
+			days = fmt.Sprint(c.DaysLeft)
+		}
+		subject := c.Subject
+		if subject == "" {
+			subject = "-"
+		}
+		if _, err := fmt.Fprintf(w, "%-32s %-10s %6s  %-40s %s\n", c.Name, expires, days, subject, strings.Join(c.VServers, ",")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCertsJSON writes the certificate inventory as a JSON array.
+func WriteCertsJSON(w io.Writer, certs []CertInfo) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(certs)
+}
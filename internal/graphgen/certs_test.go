@@ -0,0 +1,162 @@
+package graphgen
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a self-signed certificate to dir, PEM encoded unless
+// the file name ends in .der.
+func writeTestCert(t *testing.T, dir, file, cn string, sans []string, notAfter time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     sans,
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := der
+	if !strings.HasSuffix(file, ".der") {
+		data = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	if err := os.WriteFile(filepath.Join(dir, file), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const certConfig = `add ssl certkey ca_root -cert root.der
+add ssl certkey cert_app -cert /nsconfig/ssl/app.pem -key /nsconfig/ssl/app.key
+add ssl certkey cert_soon -cert soon.pem
+add ssl certkey cert_old -cert old.pem
+add ssl certkey cert_missing -cert missing.pem
+add lb vserver lb_app SSL 10.0.0.1 443
+add lb vserver lb_old SSL 10.0.0.2 443
+link ssl certkey cert_app ca_root
+bind ssl vserver lb_app -certkeyName cert_app
+bind ssl vserver lb_old -certkeyName cert_old
+`
+
+func TestCerts(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeTestCert(t, dir, "root.der", "Example Root CA", nil, now.AddDate(5, 0, 0))
+	writeTestCert(t, dir, "app.pem", "app.example.com", []string{"app.example.com", "www.example.com"}, now.AddDate(1, 0, 0))
+	writeTestCert(t, dir, "soon.pem", "soon.example.com", nil, now.AddDate(0, 0, 10))
+	writeTestCert(t, dir, "old.pem", "old.example.com", nil, now.AddDate(0, 0, -1))
+
+	ns := parseConfig(t, certConfig)
+	ns.LoadCerts(dir, 30)
+
+	colors := map[string]string{
+		"ca_root":      "",
+		"cert_app":     "",
+		"cert_soon":    certColors["expiring"],
+		"cert_old":     certColors["expired"],
+		"cert_missing": "",
+	}
+	for name, color := range colors {
+		n := findNode(ns, name)
+		if n == nil {
+			t.Fatalf("missing node %q", name)
+		}
+		if n.color != color {
+			t.Errorf("%s color = %q, want %q", name, n.color, color)
+		}
+	}
+
+	certs := ns.Certs()
+	got := map[string]CertInfo{}
+	names := []string{}
+	for _, c := range certs {
+		got[c.Name] = c
+		names = append(names, c.Name)
+	}
+	// soonest to expire first, unknown expiry last
+	wantOrder := []string{"cert_old", "cert_soon", "cert_app", "ca_root", "cert_missing"}
+	if !slices.Equal(names, wantOrder) {
+		t.Errorf("Certs() order = %v, want %v", names, wantOrder)
+	}
+
+	app := got["cert_app"]
+	if app.Subject != "CN=app.example.com" {
+		t.Errorf("cert_app subject = %q", app.Subject)
+	}
+	if !slices.Equal(app.SANs, []string{"app.example.com", "www.example.com"}) {
+		t.Errorf("cert_app SANs = %v", app.SANs)
+	}
+	if got["ca_root"].Subject != "CN=Example Root CA" || got["ca_root"].NSType != "CACert" {
+		t.Errorf("ca_root = %+v, want DER cert read as CACert", got["ca_root"])
+	}
+	if got["cert_old"].DaysLeft >= 0 {
+		t.Errorf("cert_old days left = %d, want negative", got["cert_old"].DaysLeft)
+	}
+
+	// the root protects lb_app through the chained cert_app
+	for name, want := range map[string][]string{
+		"ca_root":      {"lb_app"},
+		"cert_app":     {"lb_app"},
+		"cert_old":     {"lb_old"},
+		"cert_missing": {},
+	} {
+		if !slices.Equal(got[name].VServers, want) {
+			t.Errorf("%s vservers = %v, want %v", name, got[name].VServers, want)
+		}
+	}
+}
+
+func TestResolveCertFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"app.pem", "sub/nested.pem"} {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := map[string]string{
+		"/nsconfig/ssl/app.pem":        "app.pem",
+		"/nsconfig/ssl/sub/nested.pem": "sub/nested.pem",
+		"app.pem":                      "app.pem",
+		"/var/certs/app.pem":           "app.pem",
+	}
+	for certFile, want := range tests {
+		if got := resolveCertFile(dir, certFile); got != filepath.Join(dir, want) {
+			t.Errorf("resolveCertFile(%q) = %q, want %q", certFile, got, filepath.Join(dir, want))
+		}
+	}
+}
+
+func TestWriteCertsTable(t *testing.T) {
+	certs := []CertInfo{{Name: "cert_app", VServers: []string{"lb_app"}}}
+	var b strings.Builder
+	if err := WriteCertsTable(&b, certs); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "NAME") || !strings.Contains(b.String(), "cert_app") {
+		t.Errorf("WriteCertsTable() output:\n%s", b.String())
+	}
+	if err := WriteCertsTable(failingWriter{}, nil); err == nil {
+		t.Error("WriteCertsTable() to a failing writer returned nil error")
+	}
+}
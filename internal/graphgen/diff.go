@@ -0,0 +1,201 @@
+package graphgen
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+var diffColors = map[string]string{
+	"added":   "green",
+	"removed": "red",
+	"changed": "#ffbf00", // amber
+}
+
+// DiffChange describes a single node or edge that differs between two configs.
+type DiffChange struct {
+	Kind   string // added, removed or changed
+	Object string // node or edge
+	NSType string
+	Name   string
+	Detail string
+}
+
+func (c DiffChange) String() string {
+	symbol := map[string]string{"added": "+", "removed": "-", "changed": "~"}[c.Kind]
+	s := fmt.Sprintf("%s %-4s %-16s %s", symbol, c.Object, c.NSType, c.Name)
+	if c.Detail != "" {
+		s = fmt.Sprintf("%s (%s)", s, c.Detail)
+	}
+	return s
+}
+
+// nodeKey is the stable identity of a node across configs. Labels combine
+// name and IP, so a server whose address changes keeps the same key.
+func nodeKey(n nsNode) string {
+	if n.name != "" {
+		return n.name
+	}
+	return n.ip
+}
+
+func nodeDetail(n nsNode) string {
+	parts := []string{}
+	if n.ip != "" {
+		parts = append(parts, "ip "+n.ip)
+	}
+	if n.port != "" {
+		parts = append(parts, "port "+n.port)
+	}
+	if n.protocol != "" {
+		parts = append(parts, "protocol "+n.protocol)
+	}
+	if len(n.attrs) > 0 {
+		parts = append(parts, attrString(n.attrs, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+type diffEdge struct {
+	from  string
+	to    string
+	edges []nsEdge
+}
+
+func (ns *NSGraph) diffEdges() map[string]*diffEdge {
+	keys := map[string]string{}
+	for _, n := range ns.Nodes {
+		keys[n.label] = nodeKey(n)
+	}
+	edges := map[string]*diffEdge{}
+	for _, e := range ns.Edges {
+		from, to := keys[e.from], keys[e.to]
+		if from == "" || to == "" {
+			continue
+		}
+		k := from + " -> " + to
+		if _, ok := edges[k]; !ok {
+			edges[k] = &diffEdge{from: from, to: to}
+		}
+		edges[k].edges = append(edges[k].edges, e)
+	}
+	return edges
+}
+
+func edgeLabels(edges []nsEdge) string {
+	labels := []string{}
+	for _, e := range edges {
+		if len(e.attrs) > 0 {
+			labels = append(labels, fmt.Sprintf("%s [%s]", e.label, attrString(e.attrs, " ")))
+			continue
+		}
+		labels = append(labels, e.label)
+	}
+	slices.Sort(labels)
+	return strings.Join(labels, ", ")
+}
+
+// Diff compares two parsed configs and returns a merged graph, with added,
+// removed and changed nodes and edges coloured, alongside the list of changes.
+func Diff(a, b *NSGraph) (*NSGraph, []DiffChange) {
+	slog.Info("comparing configs")
+	changes := []DiffChange{}
+	merged := New(b.Rankdir, []string{}, []string{}, []string{})
+
+	aNodes := map[string]nsNode{}
+	for _, n := range a.Nodes {
+		aNodes[nodeKey(n)] = n
+	}
+	bNodes := map[string]nsNode{}
+	for _, n := range b.Nodes {
+		bNodes[nodeKey(n)] = n
+	}
+
+	// labels maps a node key to its label in the merged graph.
+	labels := map[string]string{}
+	for _, n := range b.Nodes {
+		k := nodeKey(n)
+		labels[k] = n.label
+		old, ok := aNodes[k]
+		switch {
+		case !ok:
+			n.color = diffColors["added"]
+			changes = append(changes, DiffChange{Kind: "added", Object: "node", NSType: n.nstype, Name: k, Detail: nodeDetail(n)})
+		case old.nstype != n.nstype || nodeDetail(old) != nodeDetail(n):
+			n.color = diffColors["changed"]
+			detail := fmt.Sprintf("%s -> %s", nodeDetail(old), nodeDetail(n))
+			if old.nstype != n.nstype {
+				detail = fmt.Sprintf("type %s -> %s; %s", old.nstype, n.nstype, detail)
+			}
+			changes = append(changes, DiffChange{Kind: "changed", Object: "node", NSType: n.nstype, Name: k, Detail: detail})
+		}
+		merged.Nodes = append(merged.Nodes, n)
+	}
+	for _, n := range a.Nodes {
+		k := nodeKey(n)
+		if _, ok := bNodes[k]; ok {
+			continue
+		}
+		labels[k] = n.label
+		n.color = diffColors["removed"]
+		changes = append(changes, DiffChange{Kind: "removed", Object: "node", NSType: n.nstype, Name: k, Detail: nodeDetail(n)})
+		merged.Nodes = append(merged.Nodes, n)
+	}
+
+	aEdges := a.diffEdges()
+	bEdges := b.diffEdges()
+	addEdges := func(de *diffEdge, kind string) {
+		for _, e := range de.edges {
+			e.from = labels[de.from]
+			e.to = labels[de.to]
+			if kind != "" {
+				e.color = diffColors[kind]
+			}
+			merged.Edges = append(merged.Edges, e)
+		}
+	}
+	for _, k := range sortedKeys(bEdges) {
+		de := bEdges[k]
+		old, ok := aEdges[k]
+		switch {
+		case !ok:
+			addEdges(de, "added")
+			changes = append(changes, DiffChange{Kind: "added", Object: "edge", Name: k, Detail: edgeLabels(de.edges)})
+		case edgeLabels(old.edges) != edgeLabels(de.edges):
+			addEdges(de, "changed")
+			changes = append(changes, DiffChange{Kind: "changed", Object: "edge", Name: k, Detail: fmt.Sprintf("%s -> %s", edgeLabels(old.edges), edgeLabels(de.edges))})
+		default:
+			addEdges(de, "")
+		}
+	}
+	for _, k := range sortedKeys(aEdges) {
+		if _, ok := bEdges[k]; ok {
+			continue
+		}
+		de := aEdges[k]
+		addEdges(de, "removed")
+		changes = append(changes, DiffChange{Kind: "removed", Object: "edge", Name: k, Detail: edgeLabels(de.edges)})
+	}
+
+	slices.SortStableFunc(changes, func(x, y DiffChange) int {
+		if x.Object != y.Object {
+			return strings.Compare(y.Object, x.Object)
+		}
+		if x.NSType != y.NSType {
+			return strings.Compare(x.NSType, y.NSType)
+		}
+		return strings.Compare(x.Name, y.Name)
+	})
+	slog.Info("compare complete", "changes", len(changes))
+	return merged, changes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
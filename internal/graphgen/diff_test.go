@@ -0,0 +1,87 @@
+package graphgen
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const diffBefore = `add server srv1 10.0.0.10
+add server srv_old 10.0.0.30
+add service svc1 srv1 HTTP 80
+add lb vserver lb_app HTTP 10.0.0.1 80
+bind lb vserver lb_app svc1
+`
+
+const diffAfter = `add server srv1 10.0.0.11
+add server srv_new 10.0.0.40
+add service svc1 srv1 HTTP 80
+add service svc2 srv_new HTTP 80
+add lb vserver lb_app HTTP 10.0.0.1 80
+bind lb vserver lb_app svc1
+bind lb vserver lb_app svc2
+`
+
+func TestDiff(t *testing.T) {
+	merged, changes := Diff(parseConfig(t, diffBefore), parseConfig(t, diffAfter))
+
+	want := map[string]string{
+		"node srv1":            "changed",
+		"node srv_old":         "removed",
+		"node srv_new":         "added",
+		"node svc2":            "added",
+		"edge lb_app -> svc2":  "added",
+		"edge svc2 -> srv_new": "added",
+	}
+	got := map[string]string{}
+	for _, c := range changes {
+		got[c.Object+" "+c.Name] = c.Kind
+	}
+	for k, kind := range want {
+		if got[k] != kind {
+			t.Errorf("change %q = %q, want %q", k, got[k], kind)
+		}
+	}
+	for k, kind := range got {
+		if _, ok := want[k]; !ok {
+			t.Errorf("unexpected %s change %q", kind, k)
+		}
+	}
+
+	colors := map[string]string{
+		"srv1 | 10.0.0.11":    diffColors["changed"],
+		"srv_old | 10.0.0.30": diffColors["removed"],
+		"srv_new | 10.0.0.40": diffColors["added"],
+		"svc1":                "",
+	}
+	for label, color := range colors {
+		n := merged.getNodeByLabel(label)
+		if n == nil {
+			t.Errorf("merged graph missing node %q", label)
+			continue
+		}
+		if n.color != color {
+			t.Errorf("node %q color = %q, want %q", label, n.color, color)
+		}
+	}
+	if e := findEdge(merged, "lb_app", "svc2"); e == nil || e.color != diffColors["added"] {
+		t.Errorf("added edge lb_app -> svc2 = %+v", e)
+	}
+	if e := findEdge(merged, "lb_app", "svc1"); e == nil || e.color != "" {
+		t.Errorf("unchanged edge lb_app -> svc1 = %+v", e)
+	}
+}
+
+func TestDiffChangeString(t *testing.T) {
+	c := DiffChange{Kind: "changed", Object: "node", NSType: "Server", Name: "srv1", Detail: "ip 10.0.0.10 -> ip 10.0.0.11"}
+	want := "~ node Server           srv1 (ip 10.0.0.10 -> ip 10.0.0.11)"
+	if got := c.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseMissingFile(t *testing.T) {
+	ns := New("LR", []string{}, []string{}, []string{})
+	if err := ns.Parse(filepath.Join(t.TempDir(), "missing.conf")); err == nil {
+		t.Error("Parse() of a missing file returned nil error")
+	}
+}
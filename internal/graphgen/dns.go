@@ -0,0 +1,86 @@
+package graphgen
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// addDNSRecord adds an address or alias record for host and links it to the
+// address or canonical name it resolves to, e.g. add dns addRec <host> <ip>.
+func (ns *NSGraph) addDNSRecord(host, target, recordType string) {
+	ns.addNode("DNSRecord", host, "", "", "")
+	ns.setNodeAttr(host, "type", recordType)
+	if ns.getNodeIndex(target) == nil {
+		if isIPAddress(target) {
+			// a vserver added later upgrades this to a VIP
+			ns.addNode("Server", target, "", "", "")
+		} else {
+			// canonical names are commonly GSLB domains
+			ns.addNode("DomainName", target, "", "", "")
+		}
+	}
+	ns.addEdge(host, target, "", recordType)
+}
+
+// dnsNameServer is a name server named by an nsRec or soaRec record.
+type dnsNameServer struct {
+	server string
+	record string
+	line   int // line of the nsRec or soaRec
+}
+
+// addDNSNameServer records a name server for domain. Records are kept until
+// Parse has seen every zone, since nsRec and soaRec lines are also used for
+// delegations and zones the appliance does not serve.
+func (ns *NSGraph) addDNSNameServer(domain, server, record string) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	ns.nameServers[domain] = append(ns.nameServers[domain], dnsNameServer{server: server, record: record, line: ns.lineNum})
+}
+
+// dnsZoneFor returns the longest zone that name belongs to, or an empty
+// string if it is in none of them.
+func dnsZoneFor(name string, zones []string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	match := ""
+	for _, zone := range zones {
+		z := strings.ToLower(strings.TrimSuffix(zone, "."))
+		if (name == z || strings.HasSuffix(name, "."+z)) && len(z) > len(match) {
+			match = zone
+		}
+	}
+	return match
+}
+
+// linkDNSZones links each zone to the records it holds and to the name
+// servers named by its own nsRec and soaRec records.
+func (ns *NSGraph) linkDNSZones() {
+	zones := []string{}
+	for _, n := range ns.Nodes {
+		if n.nstype == "DNSZone" {
+			zones = append(zones, n.name)
+		}
+	}
+	if len(zones) < 1 {
+		return
+	}
+	slog.Info("linking dns zones", "zones", len(zones))
+
+	for _, n := range ns.Nodes {
+		if n.nstype != "DNSRecord" {
+			continue
+		}
+		if zone := dnsZoneFor(n.name, zones); zone != "" {
+			ns.addEdge(zone, n.name, "", "RECORD")
+		}
+	}
+	for _, zone := range zones {
+		for _, s := range ns.nameServers[strings.ToLower(strings.TrimSuffix(zone, "."))] {
+			if ns.getNodeIndex(s.server) == nil {
+				ns.addNode("NameServer", s.server, "", "", "")
+				// nodes added after the scan would otherwise point at the last line
+				ns.Nodes[*ns.getNodeIndex(s.server)].line = s.line
+			}
+			ns.addEdge(zone, s.server, "", s.record)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package graphgen
+
+import "testing"
+
+const dnsConfig = `add dns nsRec example.com ns1.example.com -TTL 3600
+add dns soaRec example.com -originServer ns0.example.com -contact admin.example.com
+add dns nsRec other.org ns1.other.org
+add dns nsRec delegated.example.com ns9.partner.net
+add dns addRec www.example.com 10.0.0.1
+add dns addRec ns1.example.com 10.0.0.53
+add dns zone example.com -proxyMode NO
+add dns zone other.org -proxyMode NO
+add service svc_adns 10.0.0.53 ADNS 53
+`
+
+func TestLinkDNSZones(t *testing.T) {
+	ns := parseConfig(t, dnsConfig)
+
+	for _, want := range []struct{ from, to, protocol string }{
+		{"example.com", "ns1.example.com", "NS"},
+		{"example.com", "ns0.example.com", "SOA"},
+		{"example.com", "www.example.com", "RECORD"},
+		{"other.org", "ns1.other.org", "NS"},
+	} {
+		if !hasEdgeProtocol(ns, want.from, want.to, want.protocol) {
+			t.Errorf("missing %s edge %q -> %q", want.protocol, want.from, want.to)
+		}
+	}
+
+	for _, e := range ns.Edges {
+		if e.from == "example.com" && e.to == "ns1.other.org" || e.from == "other.org" && e.to == "ns1.example.com" {
+			t.Errorf("zone linked to another zone's name server: %q -> %q", e.from, e.to)
+		}
+		if e.protocol == "ZONE" {
+			t.Errorf("unexpected ZONE edge %q -> %q", e.from, e.to)
+		}
+	}
+
+	// an nsRec on its own is a delegation, not a zone
+	if n := findNode(ns, "delegated.example.com"); n != nil {
+		t.Errorf("nsRec created node %q of type %s", n.name, n.nstype)
+	}
+	if n := findNode(ns, "ns9.partner.net"); n != nil {
+		t.Errorf("unexpected name server node %q", n.name)
+	}
+}
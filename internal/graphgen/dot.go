@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/emicklei/dot"
 )
@@ -36,35 +37,87 @@ var dotEdgeAttrs = []dotEdgeAttribute{
 	{value: "SAML", color: "green"},
 	{value: "53", color: "hotpink"}, // DNS
 	{value: "DNS", color: "hotpink"},
+	{value: "ADNS", color: "hotpink"},
+	{value: "ADNS_TCP", color: "hotpink"},
+	{value: "A", color: "hotpink"},
+	{value: "AAAA", color: "hotpink"},
+	{value: "CNAME", color: "hotpink"},
+	{value: "LDAPS", color: "navy"}, // matched before the port, e.g. LDAP_TLS on 389
+	{value: "LDAP_TLS", color: "navy"},
 	{value: "389", color: "orange"}, // LDAP
 	{value: "LDAP", color: "orange"},
 	{value: "636", color: "navy"},     // LDAPS
 	{value: "1812", color: "magenta"}, // RADIUS
 	{value: "RADIUS", color: "magenta"},
 	{value: "CERT", color: "greenyellow"},
+	{value: "SNICERT", color: "yellowgreen"},
+	{value: "CACERT", color: "mediumseagreen"},
+	{value: "CHAIN", color: "darkgreen"},
+	{value: "SSLPROFILE", color: "darkseagreen"},
+	{value: "CIPHER", color: "darkseagreen"},
 	{value: "STA", color: "cadetblue"},
 	{value: "BASETHEME", color: "black"},
 	{value: "LOGINSCHEMA", color: "violet"},
 	{value: "NFACTOR", color: "pink"},
+	{value: "AUTHN", color: "lightcoral"},
+	{value: "AUTHNPROFILE", color: "lightcoral"},
+	{value: "MONITOR", color: "steelblue"},
+	{value: "INVOKE", color: "purple"},
+	{value: "REDIRECT", color: "chocolate"},
 }
 
 var dotNodeAttrs = []dotNodeAttribute{
 	{nstype: "Unknown", fillcolor: "magenta", shape: "rectangle", style: "rounded"},
+	{nstype: "AAAGroup", fillcolor: "lightcoral", shape: "tab", style: "rounded,filled"},
+	{nstype: "AAAUser", fillcolor: "lightcoral", shape: "rectangle", style: "rounded,filled"},
+	{nstype: "AppFWPolicy", fillcolor: "indianred", shape: "house", style: "rounded,filled"},
+	{nstype: "AppFWProfile", fillcolor: "indianred", shape: "invhouse", style: "rounded,filled"},
 	{nstype: "AuthAction", fillcolor: "lightcoral", shape: "invhouse", style: "rounded,filled"},
+	{nstype: "AuthnProfile", fillcolor: "lightcoral", shape: "note", style: "rounded,filled"},
+	{nstype: "AuthorizationPolicy", fillcolor: "lightcoral", shape: "house", style: "rounded,filled"},
 	{nstype: "AuthPolicy", fillcolor: "lightcoral", shape: "house", style: "rounded,filled"},
 	{nstype: "AuthVServer", fillcolor: "lightcoral", shape: "house", style: "rounded,filled"},
+	{nstype: "BotPolicy", fillcolor: "rosybrown", shape: "house", style: "rounded,filled"},
+	{nstype: "BotProfile", fillcolor: "rosybrown", shape: "invhouse", style: "rounded,filled"},
+	{nstype: "CacheContentGroup", fillcolor: "khaki", shape: "folder", style: "rounded,filled"},
+	{nstype: "CachePolicy", fillcolor: "khaki", shape: "house", style: "rounded,filled"},
+	{nstype: "CACert", fillcolor: "mediumseagreen", shape: "cds", style: "rounded,filled"},
 	{nstype: "Cert", fillcolor: "greenyellow", shape: "cds", style: "rounded,filled"},
+	{nstype: "CipherGroup", fillcolor: "darkseagreen", shape: "folder", style: "rounded,filled"},
+	{nstype: "ClientlessAccessPolicy", fillcolor: "turquoise", shape: "house", style: "rounded,filled"},
+	{nstype: "ClientlessAccessProfile", fillcolor: "turquoise", shape: "folder", style: "rounded,filled"},
+	{nstype: "CMPAction", fillcolor: "lightcyan", shape: "invhouse", style: "rounded,filled"},
+	{nstype: "CMPPolicy", fillcolor: "lightcyan", shape: "house", style: "rounded,filled"},
 	{nstype: "CSAction", fillcolor: "lightsalmon", shape: "invhouse", style: "rounded,filled"},
 	{nstype: "CSPolicy", fillcolor: "lightsalmon", shape: "house", style: "rounded,filled"},
 	{nstype: "CSVServer", fillcolor: "lightsalmon", shape: "house", style: "rounded,filled"},
+	{nstype: "DataSet", fillcolor: "wheat", shape: "note", style: "rounded,filled"},
+	{nstype: "DNSRecord", fillcolor: "hotpink", shape: "note", style: "rounded,filled"},
+	{nstype: "DNSView", fillcolor: "hotpink", shape: "tab", style: "rounded,filled"},
+	{nstype: "DNSZone", fillcolor: "hotpink", shape: "folder", style: "rounded,filled"},
 	{nstype: "DomainName", fillcolor: "aqua", shape: "house", style: "rounded,filled"},
+	{nstype: "FEOAction", fillcolor: "honeydew", shape: "invhouse", style: "rounded,filled"},
+	{nstype: "FEOPolicy", fillcolor: "honeydew", shape: "house", style: "rounded,filled"},
 	{nstype: "GSLBService", fillcolor: "lightblue", shape: "rectangle", style: "rounded,filled"},
 	{nstype: "GSLBGroup", fillcolor: "lightblue", shape: "rectangle", style: "rounded,filled"},
 	{nstype: "GSLBVServer", fillcolor: "lightblue", shape: "house", style: "rounded,filled"},
+	{nstype: "HTMLPage", fillcolor: "lightyellow", shape: "note", style: "rounded,filled"},
+	{nstype: "HTTPCallout", fillcolor: "plum", shape: "cds", style: "rounded,filled"},
+	{nstype: "ICAAccessProfile", fillcolor: "mediumturquoise", shape: "note", style: "rounded,filled"},
+	{nstype: "ICAAction", fillcolor: "mediumturquoise", shape: "invhouse", style: "rounded,filled"},
+	{nstype: "ICALatencyProfile", fillcolor: "mediumturquoise", shape: "note", style: "rounded,filled"},
+	{nstype: "ICAPolicy", fillcolor: "mediumturquoise", shape: "house", style: "rounded,filled"},
+	{nstype: "IntranetApp", fillcolor: "paleturquoise", shape: "component", style: "rounded,filled"},
 	{nstype: "LBGroup", fillcolor: "lightgoldenrodyellow", shape: "rectangle", style: "rounded,filled"},
 	{nstype: "LBVServer", fillcolor: "lightgoldenrodyellow", shape: "house", style: "rounded,filled"},
+	{nstype: "LoginSchema", fillcolor: "violet", shape: "note", style: "rounded,filled"},
+	{nstype: "LoginSchemaPolicy", fillcolor: "violet", shape: "house", style: "rounded,filled"},
+	{nstype: "Monitor", fillcolor: "lightsteelblue", shape: "component", style: "rounded,filled"},
+	{nstype: "NameServer", fillcolor: "hotpink", shape: "rectangle", style: "rounded,filled"},
 	{nstype: "Netscaler", fillcolor: "aqua", shape: "doublecircle", style: "filled"},
+	{nstype: "PatSet", fillcolor: "wheat", shape: "note", style: "rounded,filled"},
 	{nstype: "Policy", fillcolor: "lightpink", shape: "folder", style: "rounded,filled"},
+	{nstype: "PolicyExpression", fillcolor: "lavender", shape: "note", style: "rounded,filled"},
 	{nstype: "PolicyLabel", fillcolor: "lightpink", shape: "tab", style: "rounded,filled"},
 	{nstype: "PortalTheme", fillcolor: "turquoise", shape: "note", style: "rounded,filled"},
 	{nstype: "ResponderAction", fillcolor: "lightyellow", shape: "invhouse", style: "rounded,filled"},
@@ -76,7 +129,17 @@ var dotNodeAttrs = []dotNodeAttribute{
 	{nstype: "ServiceGroup", fillcolor: "lightgrey", shape: "rectangle", style: "rounded,filled"},
 	{nstype: "SessionAction", fillcolor: "palegreen", shape: "invhouse", style: "rounded,filled"},
 	{nstype: "SessionPolicy", fillcolor: "palegreen", shape: "house", style: "rounded,filled"},
+	{nstype: "SSLProfile", fillcolor: "darkseagreen", shape: "note", style: "rounded,filled"},
 	{nstype: "STA", fillcolor: "lightblue", shape: "rectangle", style: "rounded,filled"},
+	{nstype: "StringMap", fillcolor: "wheat", shape: "note", style: "rounded,filled"},
+	{nstype: "TrafficAction", fillcolor: "palegreen", shape: "invhouse", style: "rounded,filled"},
+	{nstype: "TrafficPolicy", fillcolor: "palegreen", shape: "house", style: "rounded,filled"},
+	{nstype: "TransformAction", fillcolor: "thistle", shape: "invhouse", style: "rounded,filled"},
+	{nstype: "TransformPolicy", fillcolor: "thistle", shape: "house", style: "rounded,filled"},
+	{nstype: "TransformProfile", fillcolor: "thistle", shape: "folder", style: "rounded,filled"},
+	{nstype: "URL", fillcolor: "paleturquoise", shape: "cds", style: "rounded,filled"},
+	{nstype: "URLPath", fillcolor: "paleturquoise", shape: "house", style: "rounded,filled"},
+	{nstype: "VPNURL", fillcolor: "paleturquoise", shape: "tab", style: "rounded,filled"},
 	{nstype: "VPNVServer", fillcolor: "turquoise", shape: "house", style: "rounded,filled"},
 	{nstype: "WI", fillcolor: "turquoise", shape: "polygon", style: "rounded,filled"},
 	{nstype: "VIP", fillcolor: "yellow", shape: "doublecircle", style: "filled"},
@@ -89,16 +152,36 @@ func (ns *NSGraph) ExportDot(outputFile string, stdout bool) {
 
 	for _, v := range ns.Nodes {
 		attr := getDotNodeAttribute(v.nstype)
+		if v.color != "" {
+			attr.fillcolor = v.color
+			if !strings.Contains(attr.style, "filled") {
+				attr.style += ",filled"
+			}
+		}
+		graph := ns.Graph
+		if v.cluster != "" {
+			graph = ns.Graph.Subgraph(v.cluster, dot.ClusterOption{})
+		}
+		var node dot.Node
 		if v.highlighted {
 
-			ns.Graph.Node(v.label).Attr("fillcolor", attr.fillcolor).Attr("color", dotHighlightColor).Attr("shape", attr.shape).Attr("style", attr.style+",bold")
+			node = graph.Node(v.label).Attr("fillcolor", attr.fillcolor).Attr("color", dotHighlightColor).Attr("shape", attr.shape).Attr("style", attr.style+",bold")
 		} else {
-			ns.Graph.Node(v.label).Attr("fillcolor", attr.fillcolor).Attr("shape", attr.shape).Attr("style", attr.style)
+			node = graph.Node(v.label).Attr("fillcolor", attr.fillcolor).Attr("shape", attr.shape).Attr("style", attr.style)
+		}
+		if shown := nodeLabelAttrs(v); len(shown) > 0 {
+			node.Label(v.label + "\n" + strings.Join(shown, "\n"))
+		}
+		if len(v.attrs) > 0 {
+			node.Attr("tooltip", attrString(v.attrs, "\n"))
 		}
 	}
 
-	for _, v := range ns.Edges {
+	for _, v := range ns.orderedEdges() {
 		attr := getDotEdgeAttribute(v.port, v.protocol)
+		if v.color != "" {
+			attr.color = v.color
+		}
 		from, found_from := ns.Graph.FindNodeById(v.from)
 		if !found_from {
 			continue
@@ -107,7 +190,10 @@ func (ns *NSGraph) ExportDot(outputFile string, stdout bool) {
 		if !found_to {
 			continue
 		}
-		ns.Graph.Edge(from, to, v.label).Attr("color", attr.color)
+		edge := ns.Graph.Edge(from, to, v.label).Attr("color", attr.color)
+		if len(v.attrs) > 0 {
+			edge.Attr("tooltip", attrString(v.attrs, "\n"))
+		}
 	}
 
 	if stdout {
@@ -0,0 +1,93 @@
+package graphgen
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const gslbConfig = `add gslb site site_east 10.1.0.5 -publicIP 203.0.113.5
+add gslb service gsvc_east 10.1.0.80 HTTP 80 -publicIP 203.0.113.80 -publicPort 80 -siteName site_east
+add gslb vserver gvs_app HTTP -lbMethod RTT -persistenceType SOURCEIP
+bind gslb vserver gvs_app -serviceName gsvc_east
+`
+
+// priorityConfig binds policies out of priority order, with an unprioritised
+// service binding in between.
+const priorityConfig = `add server srv1 10.0.0.10
+add service svc1 srv1 HTTP 80
+add lb vserver lb1 HTTP 10.0.0.1 80
+add responder policy pol_a true DROP
+add responder policy pol_b true DROP
+add responder policy pol_c true DROP
+bind lb vserver lb1 -policyName pol_c -priority 300 -gotoPriorityExpression END -type REQUEST
+bind lb vserver lb1 svc1
+bind lb vserver lb1 -policyName pol_a -priority 100 -gotoPriorityExpression END -type REQUEST
+bind lb vserver lb1 -policyName pol_b -priority 20 -gotoPriorityExpression END -type REQUEST
+`
+
+// priorityLabels are the lb1 edge labels in the order they must be drawn:
+// unprioritised edges first, then numerically by priority.
+var priorityLabels = []string{`"HTTP"`, `"#20 | HTTP"`, `"#100 | HTTP"`, `"#300 | HTTP"`}
+
+// wantInOrder reports an error unless each of want appears in out after the
+// one before it.
+func wantInOrder(t *testing.T, out string, want []string) {
+	t.Helper()
+	pos := 0
+	for _, w := range want {
+		i := strings.Index(out[pos:], w)
+		if i == -1 {
+			t.Errorf("%s missing or out of order in:\n%s", w, out)
+			return
+		}
+		pos += i + len(w)
+	}
+}
+
+func TestOrderedEdges(t *testing.T) {
+	ns := parseConfig(t, priorityConfig)
+	got := []string{}
+	for _, e := range ns.orderedEdges() {
+		if e.from == "lb1" {
+			got = append(got, e.to+" "+e.label)
+		}
+	}
+	want := []string{"svc1 HTTP", "pol_b #20 | HTTP", "pol_a #100 | HTTP", "pol_c #300 | HTTP"}
+	if !slices.Equal(got, want) {
+		t.Errorf("orderedEdges() from lb1 = %q, want %q", got, want)
+	}
+}
+
+func TestExportDotPriorityOrder(t *testing.T) {
+	ns := parseConfig(t, priorityConfig)
+	out := filepath.Join(t.TempDir(), "out.dot")
+	ns.ExportDot(out, false)
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := []string{}
+	for _, l := range priorityLabels {
+		labels = append(labels, "label="+l)
+	}
+	wantInOrder(t, string(b), labels)
+}
+
+func TestExportDotLabelAttrs(t *testing.T) {
+	ns := parseConfig(t, gslbConfig)
+	out := filepath.Join(t.TempDir(), "out.dot")
+	ns.ExportDot(out, false)
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `label="gvs_app\nlbMethod=RTT\npersistenceType=SOURCEIP"`) {
+		t.Errorf("gslb vserver label missing lbMethod and persistenceType:\n%s", b)
+	}
+	if !strings.Contains(string(b), `label="site_east"`) {
+		t.Errorf("gslb site cluster missing:\n%s", b)
+	}
+}
@@ -0,0 +1,305 @@
+package graphgen
+
+import (
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ruleMatch is a single host or path condition found in a policy expression.
+type ruleMatch struct {
+	nstype    string // DomainName or URLPath
+	value     string
+	condition string // e.g. HOSTNAME.ENDSWITH
+}
+
+// chainCall is one step of an expression's method chain, e.g. HOSTNAME or
+// SET_TEXT_MODE(IGNORECASE).
+type chainCall struct {
+	name string // upper case
+	args string // raw text between the parentheses, if any
+}
+
+// ruleStartRegex finds where request expressions begin, once string literals
+// have been blanked out.
+var ruleStartRegex = regexp.MustCompile(`(?i)(^|[^\w.])HTTP\.REQ\.`)
+
+// textModifiers change how a value is compared without changing what it is.
+var textModifiers = []string{"SET_TEXT_MODE", "TO_LOWER", "TO_UPPER", "HTTP_URL_SAFE"}
+
+// ruleComparisons are the string comparisons drawn as host and path matches.
+var ruleComparisons = []string{"EQ", "CONTAINS", "STARTSWITH", "ENDSWITH"}
+
+// parseRuleExpression extracts host and path conditions from an advanced
+// policy expression by walking each HTTP.REQ method chain, e.g.
+// HTTP.REQ.HOSTNAME.SET_TEXT_MODE(IGNORECASE).ENDSWITH("example.com") or
+// HTTP.REQ.URL.PATH.GET(1).EQ("images").
+func parseRuleExpression(rule string) []ruleMatch {
+	rule = unescapeExpr(rule)
+	masked := literalRegex.ReplaceAllStringFunc(rule, func(lit string) string {
+		return `"` + strings.Repeat(" ", len(lit)-2) + `"`
+	})
+	matches := []ruleMatch{}
+	for _, loc := range ruleStartRegex.FindAllStringSubmatchIndex(masked, -1) {
+		chain := parseChain(rule, loc[3])
+		if m, ok := chainMatch(chain); ok {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// parseChain reads the method chain that starts at offset, e.g.
+// HTTP.REQ.HEADER("Host").EQ("a"), stopping at the first character that
+// cannot continue it.
+func parseChain(expr string, offset int) []chainCall {
+	chain := []chainCall{}
+	i := offset
+	for i < len(expr) {
+		start := i
+		for i < len(expr) && (expr[i] == '_' || unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i]))) {
+			i++
+		}
+		if i == start {
+			break
+		}
+		call := chainCall{name: strings.ToUpper(expr[start:i])}
+		j := i
+		for j < len(expr) && expr[j] == ' ' {
+			j++
+		}
+		if j < len(expr) && expr[j] == '(' {
+			end := closingParen(expr, j)
+			if end < 0 {
+				break
+			}
+			call.args = strings.TrimSpace(expr[j+1 : end])
+			i = end + 1
+		}
+		chain = append(chain, call)
+		if i >= len(expr) || expr[i] != '.' {
+			break
+		}
+		i++
+	}
+	return chain
+}
+
+// closingParen returns the index of the parenthesis closing the one at open,
+// skipping any inside string literals, or -1 if it is not closed.
+func closingParen(expr string, open int) int {
+	depth := 0
+	quoted := false
+	for i := open; i < len(expr); i++ {
+		switch {
+		case expr[i] == '"':
+			quoted = !quoted
+		case quoted:
+		case expr[i] == '(':
+			depth++
+		case expr[i] == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// chainMatch interprets an HTTP.REQ method chain as a host or path
+// condition, if it compares the hostname, Host header or URL to a literal.
+func chainMatch(chain []chainCall) (ruleMatch, bool) {
+	if len(chain) < 3 {
+		return ruleMatch{}, false
+	}
+	rest := chain[2:]
+	var nstype, subject string
+	switch {
+	case rest[0].name == "HOSTNAME":
+		nstype, subject = "DomainName", "HOSTNAME"
+		if len(rest) > 1 && rest[1].name == "SERVER" {
+			subject += ".SERVER"
+			rest = rest[1:]
+		}
+	case rest[0].name == "HEADER":
+		if host, ok := stringLiteral(rest[0].args); !ok || !strings.EqualFold(host, "host") {
+			return ruleMatch{}, false
+		}
+		nstype, subject = "DomainName", "HEADER(HOST)"
+	case rest[0].name == "URL":
+		nstype, subject = "URLPath", "URL"
+		if len(rest) > 1 && (rest[1].name == "PATH" || rest[1].name == "PATH_AND_QUERY") {
+			subject += "." + rest[1].name
+			rest = rest[1:]
+		}
+	default:
+		return ruleMatch{}, false
+	}
+
+	for _, call := range rest[1:] {
+		switch {
+		case slices.Contains(textModifiers, call.name):
+			continue
+		case slices.Contains(ruleComparisons, call.name):
+			value, ok := stringLiteral(call.args)
+			if !ok {
+				return ruleMatch{}, false
+			}
+			return ruleMatch{nstype: nstype, value: value, condition: subject + "." + call.name}, true
+		case call.args != "":
+			// accessors such as GET(1) narrow what is compared
+			subject += "." + call.name + "(" + call.args + ")"
+		default:
+			subject += "." + call.name
+		}
+	}
+	return ruleMatch{}, false
+}
+
+// classicRuleMatches extracts the -url and -domain options of classic
+// content switching policies.
+func classicRuleMatches(term []string) []ruleMatch {
+	matches := []ruleMatch{}
+	if url := termValue(term, "-url"); url != "" {
+		matches = append(matches, ruleMatch{nstype: "URLPath", value: url, condition: "URL"})
+	}
+	if domain := termValue(term, "-domain"); domain != "" {
+		matches = append(matches, ruleMatch{nstype: "DomainName", value: domain, condition: "DOMAIN"})
+	}
+	return matches
+}
+
+// setRefRegex matches lookups against pattern sets, data sets and string
+// maps, e.g. HTTP.REQ.HOSTNAME.EQUALS_ANY("ps_hosts").
+var setRefRegex = regexp.MustCompile(`(?i)\.(EQUALS_ANY|CONTAINS_ANY|STARTSWITH_ANY|ENDSWITH_ANY|EQUALS_INDEX|CONTAINS_INDEX|STARTSWITH_INDEX|ENDSWITH_INDEX|IS_STRINGMAP_KEY|MAP_STRING|MAP_STRING_DEFAULT_TO)\("([^"]+)"`)
+
+// calloutRegex matches HTTP callout invocations, e.g. SYS.HTTP_CALLOUT(co_auth).
+var calloutRegex = regexp.MustCompile(`(?i)SYS\.HTTP_CALLOUT\(([^)\s]+)\)`)
+
+// literalRegex matches string literals, which never hold object references.
+var literalRegex = regexp.MustCompile(`"[^"]*"`)
+
+// ruleText returns the rule of an add command, either the -rule option or
+// the first positional argument after the name, e.g.
+// add responder policy <name> <rule> <action>.
+func ruleText(term []string) string {
+	rule := termValue(term, "-rule")
+	if rule == "" && len(term) > 4 && !strings.HasPrefix(term[4], "-") {
+		rule = term[4]
+	}
+	return unescapeExpr(rule)
+}
+
+// exprValue unescapes an expression option value, e.g. -hostExpr "\"host\"",
+// and strips the quotes from a single string literal.
+func exprValue(value string) string {
+	if literal, ok := stringLiteral(value); ok {
+		return literal
+	}
+	return unescapeExpr(value)
+}
+
+// stringLiteral returns the text of an expression that is a single string
+// literal, e.g. "\"https://example.com/\"", and reports whether it is one.
+func stringLiteral(value string) (string, bool) {
+	value = unescapeExpr(value)
+	if len(value) > 1 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) && strings.Count(value, `"`) == 2 {
+		return value[1 : len(value)-1], true
+	}
+	return "", false
+}
+
+func unescapeExpr(value string) string {
+	if strings.HasSuffix(value, `\`) {
+		// the closing escaped quote was trimmed along with the outer quotes
+		value += `"`
+	}
+	return strings.ReplaceAll(value, `\"`, `"`)
+}
+
+// searchRegex matches a rewrite action -search that looks up a patset or
+// dataset, e.g. -search "patset(\"ps_words\")".
+var searchRegex = regexp.MustCompile(`(?i)^(patset|dataset)\("([^"]+)"\)$`)
+
+// identRegex matches a run of name characters, e.g. exp_host or
+// HTTP.REQ.HOSTNAME; a named expression is the part before the first dot.
+var identRegex = regexp.MustCompile(`[\w#.-]+`)
+
+// addExpressionReferences links a policy, action or named expression to every
+// patset, dataset, stringmap, HTTP callout and named expression used in the
+// given expressions, labelled with how it is used.
+func (ns *NSGraph) addExpressionReferences(name string, exprs ...string) {
+	for _, expr := range exprs {
+		if expr == "" {
+			continue
+		}
+		expr = unescapeExpr(expr)
+		for _, m := range setRefRegex.FindAllStringSubmatch(expr, -1) {
+			op := strings.ToUpper(m[1])
+			nstype := "Unknown" // patsets and datasets share lookup functions
+			if strings.Contains(op, "STRING") {
+				nstype = "StringMap"
+			}
+			ns.addNode(nstype, m[2], "", "", "")
+			ns.addEdge(name, m[2], "", op)
+		}
+		for _, m := range calloutRegex.FindAllStringSubmatch(expr, -1) {
+			ns.addNode("HTTPCallout", m[1], "", "", "")
+			ns.addEdge(name, m[1], "", "CALLOUT")
+		}
+
+		// named expressions are used as bare identifiers, never inside literals
+		seen := map[string]bool{}
+		for _, token := range identRegex.FindAllString(literalRegex.ReplaceAllString(expr, ""), -1) {
+			ident, _, _ := strings.Cut(token, ".")
+			if ident == name || seen[ident] || !ns.expressions[ident] {
+				continue
+			}
+			seen[ident] = true
+			ns.addEdge(name, ident, "", "EXPRESSION")
+		}
+	}
+}
+
+// isPolicyCommand reports whether the object type of an add command is a
+// policy or named expression whose rule may reference other objects, e.g.
+// cs policy or authentication Policy.
+func isPolicyCommand(objType string) bool {
+	objType = strings.ToLower(objType)
+	return strings.HasSuffix(objType, "policy") || objType == "expression"
+}
+
+// expandPatsets replaces each patset that has entries with DomainName nodes,
+// one per entry, linked into the policies that look the patset up.
+func (ns *NSGraph) expandPatsets() {
+	slog.Info("expanding patset entries")
+	patsets := map[string]nsNode{}
+	for _, n := range ns.Nodes {
+		if n.nstype == "PatSet" && len(ns.patsetEntries[n.name]) > 0 {
+			patsets[n.name] = n
+		}
+	}
+	if len(patsets) < 1 {
+		return
+	}
+
+	edges := []nsEdge{}
+	for _, e := range ns.Edges {
+		switch {
+		case patsets[e.to].name != "":
+			for _, entry := range ns.patsetEntries[e.to] {
+				ns.addNode("DomainName", entry, "", "", "")
+				edges = append(edges, nsEdge{from: entry, to: e.from, protocol: e.protocol})
+			}
+		case patsets[e.from].name != "":
+			continue
+		default:
+			edges = append(edges, e)
+		}
+	}
+	ns.Edges = edges
+	ns.Nodes = slices.DeleteFunc(ns.Nodes, func(n nsNode) bool { return patsets[n.name].name != "" })
+}
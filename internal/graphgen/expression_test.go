@@ -0,0 +1,145 @@
+package graphgen
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// terms splits a config line the way parseNSline does.
+func terms(line string) []string {
+	re := regexp.MustCompile(`"((?:\\.|[^"\\])*)"|(\S+)`)
+	term := re.FindAllString(line, -1)
+	for i, v := range term {
+		term[i] = strings.Trim(v, "\"")
+	}
+	return term
+}
+
+func hasEdgeProtocol(ns *NSGraph, from, to, protocol string) bool {
+	for _, e := range ns.Edges {
+		if e.from == from && e.to == to && e.protocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExpressionReferences(t *testing.T) {
+	ns := parseLines(t,
+		`add policy expression exp_host "HTTP.REQ.HOSTNAME.EQ(\"www.example.com\")"`,
+		`add policy expression exp_admin "exp_host && HTTP.REQ.URL.STARTSWITH(\"/admin\")"`,
+		`add policy expression act_block true`,
+		`add responder policy pol_named "exp_admin.NOT || exp_host" act_block`,
+		`add responder policy pol_literal "HTTP.REQ.URL.CONTAINS(\"exp_host\")" act_block`,
+		`add rewrite policy pol_sets "HTTP.REQ.HOSTNAME.EQUALS_ANY(\"ps_hosts\") && SYS.HTTP_CALLOUT(co_auth).EQ(\"ok\")" NOREWRITE`,
+	)
+	tests := []struct {
+		from, to, protocol string
+		want               bool
+	}{
+		{"exp_admin", "exp_host", "EXPRESSION", true},
+		{"pol_named", "exp_admin", "EXPRESSION", true},
+		{"pol_named", "exp_host", "EXPRESSION", true},
+		{"pol_named", "act_block", "EXPRESSION", false}, // the action is not part of the rule
+		{"pol_literal", "exp_host", "EXPRESSION", false},
+		{"pol_sets", "ps_hosts", "EQUALS_ANY", true},
+		{"pol_sets", "co_auth", "CALLOUT", true},
+	}
+	for _, tt := range tests {
+		if got := hasEdgeProtocol(ns, tt.from, tt.to, tt.protocol); got != tt.want {
+			t.Errorf("edge %s -> %s (%s) = %v, want %v", tt.from, tt.to, tt.protocol, got, tt.want)
+		}
+	}
+}
+
+func TestRuleText(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`add responder policy pol1 "HTTP.REQ.URL.EQ(\"/\")" act1`, `HTTP.REQ.URL.EQ("/")`},
+		{`add cs policy pol2 -rule "HTTP.REQ.HOSTNAME.EQ(\"a\")" -action act2`, `HTTP.REQ.HOSTNAME.EQ("a")`},
+		{`add cs policy pol3 -url /images`, ``},
+		{`add responder policy pol4`, ``},
+	}
+	for _, tt := range tests {
+		if got := ruleText(terms(tt.line)); got != tt.want {
+			t.Errorf("ruleText(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseRuleExpression(t *testing.T) {
+	tests := []struct {
+		rule string
+		want []ruleMatch
+	}{
+		{
+			`HTTP.REQ.HOSTNAME.SET_TEXT_MODE(IGNORECASE).ENDSWITH(\"example.com\")`,
+			[]ruleMatch{{"DomainName", "example.com", "HOSTNAME.ENDSWITH"}},
+		},
+		{
+			`http.req.header( "host" ).eq("a.example.com")`,
+			[]ruleMatch{{"DomainName", "a.example.com", "HEADER(HOST).EQ"}},
+		},
+		{
+			`HTTP.REQ.HOSTNAME.SERVER.EQ("b.example.com")`,
+			[]ruleMatch{{"DomainName", "b.example.com", "HOSTNAME.SERVER.EQ"}},
+		},
+		{
+			`HTTP.REQ.URL.PATH.GET(1).EQ("images")`,
+			[]ruleMatch{{"URLPath", "images", "URL.PATH.GET(1).EQ"}},
+		},
+		{
+			`HTTP.REQ.URL.PATH.SET_TEXT_MODE(IGNORECASE).TO_LOWER.STARTSWITH("/api") && HTTP.REQ.HOSTNAME.EQ("c.example.com")`,
+			[]ruleMatch{
+				{"URLPath", "/api", "URL.PATH.STARTSWITH"},
+				{"DomainName", "c.example.com", "HOSTNAME.EQ"},
+			},
+		},
+		{
+			`HTTP.REQ.HEADER("X-Host").EQ("d.example.com")`,
+			[]ruleMatch{},
+		},
+		{
+			`HTTP.REQ.URL.CONTAINS("HTTP.REQ.HOSTNAME.EQ(x)")`,
+			[]ruleMatch{{"URLPath", "HTTP.REQ.HOSTNAME.EQ(x)", "URL.CONTAINS"}},
+		},
+		{
+			`HTTP.REQ.HOSTNAME.EQ(exp_host)`,
+			[]ruleMatch{},
+		},
+	}
+	for _, tt := range tests {
+		got := parseRuleExpression(tt.rule)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parseRuleExpression(%q) = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestExpandPatsets(t *testing.T) {
+	ns := parseLines(t,
+		"add policy patset ps_paths",
+		`bind policy patset ps_paths "/search?q=a,b" -index 1`,
+		"bind policy patset ps_paths /login -index 2",
+		`add responder policy pol_paths "HTTP.REQ.URL.PATH.CONTAINS_ANY(\"ps_paths\")" DROP`,
+	)
+	ns.expandPatsets()
+
+	for _, entry := range []string{"/search?q=a,b", "/login"} {
+		if n := findNode(ns, entry); n == nil || n.nstype != "DomainName" {
+			t.Errorf("missing DomainName node %q", entry)
+		}
+		if findEdge(ns, entry, "pol_paths") == nil {
+			t.Errorf("missing edge %q -> pol_paths", entry)
+		}
+	}
+	for _, part := range []string{"/search?q=a", "b", "ps_paths"} {
+		if findNode(ns, part) != nil {
+			t.Errorf("unexpected node %q", part)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/emicklei/dot"
@@ -16,21 +17,56 @@ import (
 
 var NodeTypes = []string{
 	"Unknown",
+	"AAAGroup",
+	"AAAUser",
+	"AppFWPolicy",
+	"AppFWProfile",
 	"AuthAction",
+	"AuthnProfile",
+	"AuthorizationPolicy",
 	"AuthPolicy",
 	"AuthVServer",
+	"BotPolicy",
+	"BotProfile",
+	"CacheContentGroup",
+	"CachePolicy",
+	"CACert",
 	"Cert",
+	"CipherGroup",
+	"ClientlessAccessPolicy",
+	"ClientlessAccessProfile",
+	"CMPAction",
+	"CMPPolicy",
 	"CSAction",
 	"CSPolicy",
 	"CSVServer",
+	"DataSet",
+	"DNSRecord",
+	"DNSView",
+	"DNSZone",
 	"DomainName",
+	"FEOAction",
+	"FEOPolicy",
 	"GSLBService",
 	"GSLBGroup",
 	"GSLBVServer",
+	"HTMLPage",
+	"HTTPCallout",
+	"ICAAccessProfile",
+	"ICAAction",
+	"ICALatencyProfile",
+	"ICAPolicy",
+	"IntranetApp",
 	"LBGroup",
 	"LBVServer",
+	"LoginSchema",
+	"LoginSchemaPolicy",
+	"Monitor",
+	"NameServer",
 	"Netscaler",
+	"PatSet",
 	"Policy",
+	"PolicyExpression",
 	"PolicyLabel",
 	"PortalTheme",
 	"ResponderAction",
@@ -42,7 +78,17 @@ var NodeTypes = []string{
 	"ServiceGroup",
 	"SessionAction",
 	"SessionPolicy",
+	"SSLProfile",
 	"STA",
+	"StringMap",
+	"TrafficAction",
+	"TrafficPolicy",
+	"TransformAction",
+	"TransformPolicy",
+	"TransformProfile",
+	"URL",
+	"URLPath",
+	"VPNURL",
 	"VPNVServer",
 	"WI",
 	"VIP",
@@ -57,6 +103,10 @@ type nsNode struct {
 	label       string
 	isolated    bool
 	highlighted bool
+	color       string
+	cluster     string
+	line        int
+	attrs       map[string]string
 }
 type nsEdge struct {
 	from     string
@@ -64,6 +114,8 @@ type nsEdge struct {
 	port     string
 	protocol string
 	label    string
+	color    string
+	attrs    map[string]string
 }
 
 type NSGraph struct {
@@ -71,9 +123,16 @@ type NSGraph struct {
 	IgnoreNames   []string
 	IgnoreTypes   []string
 	IsolatedNames []string
+	ExpandPatsets bool
 	Nodes         []nsNode
 	Edges         []nsEdge
 	Graph         *dot.Graph
+	lines         []string
+	lineNum       int
+	unparsed      map[string]int
+	expressions   map[string]bool            // named expressions, by name
+	nameServers   map[string][]dnsNameServer // nsRec and soaRec servers, by domain
+	patsetEntries map[string][]string        // bound patset and dataset entries, by name
 }
 
 func isIPAddress(str string) bool {
@@ -100,6 +159,19 @@ func makeEdgeLabel(port, protocol string) string {
 	return protocol
 }
 
+// makeBindLabel prefixes an edge label with the binding priority, if any, so
+// diagrams show policy evaluation order.
+func makeBindLabel(label string, attrs map[string]string) string {
+	priority := attrs["priority"]
+	if priority == "" {
+		return label
+	}
+	if label == "" {
+		return "#" + priority
+	}
+	return fmt.Sprintf("#%s | %s", priority, label)
+}
+
 func makeNodeLabel(name, ip string) string {
 	if name != "" && ip != "" {
 		return fmt.Sprintf("%s | %s", name, ip)
@@ -129,6 +201,10 @@ func New(rankDir string, ignoreNames []string, ignoreTypes []string, isolateName
 	}
 	ns.Nodes = []nsNode{}
 	ns.Edges = []nsEdge{}
+	ns.unparsed = map[string]int{}
+	ns.expressions = map[string]bool{}
+	ns.nameServers = map[string][]dnsNameServer{}
+	ns.patsetEntries = map[string][]string{}
 	// ns.Graph = dot.NewGraph(dot.Directed)
 
 	return ns
@@ -139,7 +215,7 @@ func (ns *NSGraph) Parse(inputFile string) error {
 
 	file, err := os.Open(inputFile)
 	if err != nil {
-		fmt.Println(err)
+		return err
 	}
 	defer file.Close()
 
@@ -150,15 +226,23 @@ func (ns *NSGraph) Parse(inputFile string) error {
 	ns.addNode("VIP", "Global", "0.0.0.0", "", "")
 
 	for scanner.Scan() {
+		ns.lineNum++
 		line := scanner.Text()
+		ns.lines = append(ns.lines, line)
 		// term := re.Split(line, -1)
 		term := re.FindAllString(line, -1)
 		for i, v := range term {
 			term[i] = strings.Trim(v, "\"")
 		}
-		ns.parseNSline(line)
+		if !ns.parseNSline(line) {
+			ns.addUnparsed(term)
+		}
 	}
 
+	if ns.ExpandPatsets {
+		ns.expandPatsets()
+	}
+	ns.linkDNSZones()
 	ns.updateEdges()
 	ns.pruneIgnored()
 	ns.pruneNonIsolated()
@@ -167,6 +251,16 @@ func (ns *NSGraph) Parse(inputFile string) error {
 	return nil
 }
 
+// addUnparsed counts a line that was not parsed by its command prefix, e.g.
+// "add lb monitor".
+func (ns *NSGraph) addUnparsed(term []string) {
+	if len(term) < 1 || strings.HasPrefix(term[0], "#") {
+		return
+	}
+	prefix := strings.Join(term[:min(len(term), 3)], " ")
+	ns.unparsed[prefix]++
+}
+
 func (ns *NSGraph) pruneIgnored() {
 	slog.Info("pruning ignored names and types")
 	if len(ns.IgnoreNames) < 1 && len(ns.IgnoreTypes) < 1 {
@@ -229,6 +323,7 @@ func (ns *NSGraph) pruneNonIsolated() {
 
 	newNodes := []nsNode{}
 	newEdges := []nsEdge{}
+	keptEdges := map[int]bool{}
 
 	markedNamesTo := []string{}
 	markedNamesFrom := []string{}
@@ -242,9 +337,10 @@ func (ns *NSGraph) pruneNonIsolated() {
 				break
 			}
 
-			for _, e := range ns.Edges {
+			for i, e := range ns.Edges {
 				if slices.Contains(toQueue, e.from) {
-					if !slices.Contains(newEdges, e) {
+					if !keptEdges[i] {
+						keptEdges[i] = true
 						newEdges = append(newEdges, e)
 					}
 					if !slices.Contains(markedNamesTo, e.to) {
@@ -261,9 +357,10 @@ func (ns *NSGraph) pruneNonIsolated() {
 				break
 			}
 
-			for _, e := range ns.Edges {
+			for i, e := range ns.Edges {
 				if slices.Contains(fromQueue, e.to) {
-					if !slices.Contains(newEdges, e) {
+					if !keptEdges[i] {
+						keptEdges[i] = true
 						newEdges = append(newEdges, e)
 					}
 					if !slices.Contains(markedNamesFrom, e.from) {
@@ -280,7 +377,7 @@ func (ns *NSGraph) pruneNonIsolated() {
 
 	for _, n := range ns.Nodes {
 		if slices.Contains(markedNames, n.label) {
-			if !slices.Contains(newNodes, n) {
+			if !slices.ContainsFunc(newNodes, func(m nsNode) bool { return m.label == n.label }) {
 				newNodes = append(newNodes, n)
 			}
 		}
@@ -320,6 +417,17 @@ func (ns *NSGraph) markIsolated(isolatedNames []string, highlight bool) []string
 }
 
 func (ns *NSGraph) addEdge(from, to, port, protocol string) {
+	ns.addEdgeAttrs(from, to, port, protocol, nil)
+}
+
+// addEdgeAttrs adds an edge carrying binding attributes, such as an OCSP
+// check or priority. Empty values are dropped.
+func (ns *NSGraph) addEdgeAttrs(from, to, port, protocol string, attrs map[string]string) {
+	for k, v := range attrs {
+		if v == "" {
+			delete(attrs, k)
+		}
+	}
 	protocol = strings.ToUpper(protocol)
 	if isIPAddress(from) {
 		ns.addNode("Unknown", "", from, "", "")
@@ -332,13 +440,14 @@ func (ns *NSGraph) addEdge(from, to, port, protocol string) {
 		ns.addNode("Unknown", to, "", "", "")
 	}
 
-	label := makeEdgeLabel(port, protocol)
+	label := makeBindLabel(makeEdgeLabel(port, protocol), attrs)
 	e := nsEdge{
 		from:     from,
 		to:       to,
 		port:     port,
 		protocol: protocol,
 		label:    label,
+		attrs:    attrs,
 	}
 	slog.Debug("add new", "edge", e)
 	ns.Edges = append(ns.Edges, e)
@@ -370,12 +479,17 @@ func (ns *NSGraph) addNode(nstype, name, ip, port, protocol string) {
 	if idx != -1 {
 		if nstype != "Unknown" && ns.Nodes[idx].nstype == "Unknown" {
 			ns.Nodes[idx].nstype = nstype
+			ns.Nodes[idx].line = ns.lineNum
 			slog.Debug("update nstype", "node", ns.Nodes[idx])
 		}
 		if nstype == "VIP" && ns.Nodes[idx].nstype == "Server" {
 			ns.Nodes[idx].nstype = nstype
 			slog.Debug("update nstype", "node", ns.Nodes[idx])
 		}
+		if nstype == "CACert" && ns.Nodes[idx].nstype == "Cert" {
+			ns.Nodes[idx].nstype = nstype
+			slog.Debug("update nstype", "node", ns.Nodes[idx])
+		}
 		if name != "" && ns.Nodes[idx].name == "" {
 			ns.Nodes[idx].ip = ip
 			slog.Debug("update name", "node", ns.Nodes[idx])
@@ -393,6 +507,10 @@ func (ns *NSGraph) addNode(nstype, name, ip, port, protocol string) {
 			slog.Debug("update protocol", "node", ns.Nodes[idx])
 		}
 
+		if definitionCommand(ns.currentLine(), ns.Nodes[idx].name) != "" {
+			ns.Nodes[idx].line = ns.lineNum
+		}
+
 		// update label
 		label := makeNodeLabel(ns.Nodes[idx].name, ns.Nodes[idx].ip)
 		if label != ns.Nodes[idx].label {
@@ -409,6 +527,7 @@ func (ns *NSGraph) addNode(nstype, name, ip, port, protocol string) {
 			port:     port,
 			protocol: protocol,
 			label:    label,
+			line:     ns.lineNum,
 		}
 		slog.Debug("add new", "node", n)
 		ns.Nodes = append(ns.Nodes, n)
@@ -416,6 +535,92 @@ func (ns *NSGraph) addNode(nstype, name, ip, port, protocol string) {
 
 }
 
+// setNodeAttr records an attribute, such as a monitor interval, on an
+// existing node. Empty values are ignored.
+func (ns *NSGraph) setNodeAttr(name, key, value string) {
+	if value == "" {
+		return
+	}
+	idx := ns.getNodeIndex(name)
+	if idx == nil {
+		slog.Warn("could not find node to set attribute", "name", name, "key", key)
+		return
+	}
+	if ns.Nodes[*idx].attrs == nil {
+		ns.Nodes[*idx].attrs = map[string]string{}
+	}
+	ns.Nodes[*idx].attrs[key] = value
+	slog.Debug("update attribute", "node", ns.Nodes[*idx], "key", key)
+}
+
+// setNodeCluster groups an existing node with others in the same cluster,
+// such as the GSLB site it belongs to. Empty clusters are ignored.
+func (ns *NSGraph) setNodeCluster(name, cluster string) {
+	if cluster == "" {
+		return
+	}
+	idx := ns.getNodeIndex(name)
+	if idx == nil {
+		slog.Warn("could not find node to set cluster", "name", name, "cluster", cluster)
+		return
+	}
+	ns.Nodes[*idx].cluster = cluster
+}
+
+// appendNodeAttr adds value to a comma separated list attribute, such as the
+// ciphers bound to a cipher group.
+func (ns *NSGraph) appendNodeAttr(name, key, value string) {
+	if value == "" {
+		return
+	}
+	idx := ns.getNodeIndex(name)
+	if idx != nil && ns.Nodes[*idx].attrs[key] != "" {
+		value = ns.Nodes[*idx].attrs[key] + "," + value
+	}
+	ns.setNodeAttr(name, key, value)
+}
+
+// countNodeAttr increments a counter attribute, such as the number of start
+// URL relaxations bound to an appfw profile.
+func (ns *NSGraph) countNodeAttr(name, key string) {
+	count := 0
+	idx := ns.getNodeIndex(name)
+	if idx != nil {
+		count, _ = strconv.Atoi(ns.Nodes[*idx].attrs[key])
+	}
+	ns.setNodeAttr(name, key, strconv.Itoa(count+1))
+}
+
+// labelAttrs are the attributes shown in a node's label, by node type. All
+// attributes are also available as a tooltip in DOT output.
+var labelAttrs = map[string][]string{
+	"AuthAction":  {"type", "secType", "ldapBase", "ldapBindDn", "ldapLoginName"},
+	"CACert":      {"notAfter"},
+	"Cert":        {"notAfter"},
+	"GSLBVServer": {"lbMethod", "backupLBMethod", "persistenceType"},
+}
+
+// nodeLabelAttrs returns the label attributes of n that are set, as
+// key=value strings.
+func nodeLabelAttrs(n nsNode) []string {
+	shown := []string{}
+	for _, key := range labelAttrs[n.nstype] {
+		if value := n.attrs[key]; value != "" {
+			shown = append(shown, key+"="+value)
+		}
+	}
+	return shown
+}
+
+// attrString formats attributes as sorted key=value pairs.
+func attrString(attrs map[string]string, sep string) string {
+	parts := []string{}
+	for _, k := range sortedKeys(attrs) {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, attrs[k]))
+	}
+	return strings.Join(parts, sep)
+}
+
 func (ns *NSGraph) getNodeIndex(target string) *int {
 	if target == "" {
 		return nil
@@ -444,6 +649,94 @@ func (ns *NSGraph) getNodeIndex(target string) *int {
 	*/
 }
 
+func (ns *NSGraph) currentLine() string {
+	if ns.lineNum < 1 || ns.lineNum > len(ns.lines) {
+		return ""
+	}
+	return ns.lines[ns.lineNum-1]
+}
+
+// definitionCommand returns the command prefix, e.g. "lb vserver", if line is
+// the add command that defines name.
+func definitionCommand(line, name string) string {
+	if name == "" || !strings.HasPrefix(line, "add ") {
+		return ""
+	}
+	re := regexp.MustCompile(`"((?:\\.|[^"\\])*)"|(\S+)`)
+	term := re.FindAllString(line, 5)
+	for i, v := range term {
+		term[i] = strings.Trim(v, "\"")
+	}
+	for i := 2; i < len(term) && i < 4; i++ {
+		if term[i] == name {
+			return strings.Join(term[1:i], " ")
+		}
+	}
+	return ""
+}
+
+func (ns *NSGraph) getNodeByLabel(label string) *nsNode {
+	for i, v := range ns.Nodes {
+		if v.label == label {
+			return &ns.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func (ns *NSGraph) getEdgesFrom(label string) []nsEdge {
+	edges := []nsEdge{}
+	for _, e := range ns.Edges {
+		if e.from == label {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+func (ns *NSGraph) getEdgesTo(label string) []nsEdge {
+	edges := []nsEdge{}
+	for _, e := range ns.Edges {
+		if e.to == label {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// orderedEdges returns the edges grouped by source node, in the order each
+// source first appears, with bound policies sorted by priority.
+func (ns *NSGraph) orderedEdges() []nsEdge {
+	sources := []string{}
+	groups := map[string][]nsEdge{}
+	for _, e := range ns.Edges {
+		if _, ok := groups[e.from]; !ok {
+			sources = append(sources, e.from)
+		}
+		groups[e.from] = append(groups[e.from], e)
+	}
+
+	edges := []nsEdge{}
+	for _, from := range sources {
+		group := groups[from]
+		slices.SortStableFunc(group, func(a, b nsEdge) int {
+			ap, aErr := strconv.Atoi(a.attrs["priority"])
+			bp, bErr := strconv.Atoi(b.attrs["priority"])
+			switch {
+			case aErr != nil && bErr != nil:
+				return 0
+			case aErr != nil:
+				return -1
+			case bErr != nil:
+				return 1
+			}
+			return ap - bp
+		})
+		edges = append(edges, group...)
+	}
+	return edges
+}
+
 func (ns *NSGraph) updateEdges() {
 	slog.Debug("update edges")
 	fromIdx := -1
@@ -478,6 +771,6 @@ func (ns *NSGraph) updateEdges() {
 
 		ns.Edges[i].from = fromLabel
 		ns.Edges[i].to = to_label
-		ns.Edges[i].label = makeEdgeLabel(ns.Edges[i].port, ns.Edges[i].protocol)
+		ns.Edges[i].label = makeBindLabel(makeEdgeLabel(ns.Edges[i].port, ns.Edges[i].protocol), ns.Edges[i].attrs)
 	}
 }
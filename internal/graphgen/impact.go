@@ -0,0 +1,137 @@
+package graphgen
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// ImpactEntryTypes are the node types treated as front-ends when walking
+// upstream from a backend or certificate.
+var ImpactEntryTypes = []string{
+	"VIP",
+	"DomainName",
+	"AuthVServer",
+	"CSVServer",
+	"GSLBVServer",
+	"LBVServer",
+	"VPNVServer",
+}
+
+// ImpactRoute is a single affected front-end and the path from it down to the
+// analysed target.
+type ImpactRoute struct {
+	Target    string
+	EntryType string
+	Entry     string
+	Route     []string
+}
+
+// Impact walks edges upstream from each target to every VIP, DomainName and
+// vserver that depends on it.
+func (ns *NSGraph) Impact(targets []string) []ImpactRoute {
+	routes := []ImpactRoute{}
+	nodeTypes := map[string]string{}
+	for _, n := range ns.Nodes {
+		nodeTypes[n.label] = n.nstype
+	}
+
+	for _, target := range targets {
+		idx := ns.getNodeIndex(target)
+		if idx == nil {
+			slog.Warn("could not find impact target", "name", target)
+			continue
+		}
+		start := ns.Nodes[*idx].label
+		slog.Debug("walking upstream", "target", start)
+
+		// parent records the downstream neighbour each node was reached from,
+		// giving the shortest route back to the target.
+		parent := map[string]string{start: ""}
+		order := []string{}
+		queue := []string{start}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, e := range ns.Edges {
+				if e.to != current {
+					continue
+				}
+				if _, seen := parent[e.from]; seen {
+					continue
+				}
+				parent[e.from] = current
+				order = append(order, e.from)
+				queue = append(queue, e.from)
+			}
+		}
+
+		for _, label := range order {
+			nstype := nodeTypes[label]
+			if !slices.Contains(ImpactEntryTypes, nstype) {
+				continue
+			}
+			route := []string{}
+			for step := label; step != ""; step = parent[step] {
+				route = append(route, step)
+			}
+			routes = append(routes, ImpactRoute{
+				Target:    start,
+				EntryType: nstype,
+				Entry:     label,
+				Route:     route,
+			})
+		}
+	}
+
+	slices.SortStableFunc(routes, func(a, b ImpactRoute) int {
+		if a.Target != b.Target {
+			return strings.Compare(a.Target, b.Target)
+		}
+		ai := slices.Index(ImpactEntryTypes, a.EntryType)
+		bi := slices.Index(ImpactEntryTypes, b.EntryType)
+		if ai != bi {
+			return ai - bi
+		}
+		return strings.Compare(a.Entry, b.Entry)
+	})
+	return routes
+}
+
+// HighlightImpact reduces the graph to the nodes and edges along the given
+// routes, highlighting the targets and affected front-ends.
+func (ns *NSGraph) HighlightImpact(routes []ImpactRoute) {
+	slog.Info("pruning graph to impacted routes")
+	keepNodes := map[string]bool{}
+	keepEdges := map[[2]string]bool{}
+	highlight := map[string]bool{}
+	for _, r := range routes {
+		highlight[r.Target] = true
+		highlight[r.Entry] = true
+		for i, label := range r.Route {
+			keepNodes[label] = true
+			if i > 0 {
+				keepEdges[[2]string{r.Route[i-1], label}] = true
+			}
+		}
+	}
+
+	newNodes := []nsNode{}
+	for _, n := range ns.Nodes {
+		if !keepNodes[n.label] {
+			continue
+		}
+		n.highlighted = highlight[n.label]
+		newNodes = append(newNodes, n)
+	}
+
+	newEdges := []nsEdge{}
+	for _, e := range ns.Edges {
+		if keepEdges[[2]string{e.from, e.to}] {
+			newEdges = append(newEdges, e)
+		}
+	}
+
+	ns.Nodes = newNodes
+	ns.Edges = newEdges
+}
@@ -0,0 +1,64 @@
+package graphgen
+
+import (
+	"slices"
+	"testing"
+)
+
+const impactConfig = `add server srv1 10.0.0.10
+add server srv2 10.0.0.20
+add service svc1 srv1 HTTP 80
+add service svc2 srv2 HTTP 80
+add lb vserver lb_app HTTP 10.0.0.1 80
+add lb vserver lb_other HTTP 10.0.0.2 80
+add cs vserver cs_front HTTP 10.0.0.100 80
+add cs action act_app -targetLBVserver lb_app
+add cs policy pol_app -rule true -action act_app
+bind lb vserver lb_app svc1
+bind lb vserver lb_other svc2
+bind cs vserver cs_front -policyName pol_app -priority 100
+`
+
+func TestImpact(t *testing.T) {
+	ns := parseConfig(t, impactConfig)
+	routes := ns.Impact([]string{"srv1", "missing"})
+
+	want := []ImpactRoute{
+		{"srv1 | 10.0.0.10", "VIP", "10.0.0.1", []string{"10.0.0.1", "lb_app", "svc1", "srv1 | 10.0.0.10"}},
+		{"srv1 | 10.0.0.10", "VIP", "10.0.0.100", []string{"10.0.0.100", "cs_front", "pol_app", "act_app", "lb_app", "svc1", "srv1 | 10.0.0.10"}},
+		{"srv1 | 10.0.0.10", "CSVServer", "cs_front", []string{"cs_front", "pol_app", "act_app", "lb_app", "svc1", "srv1 | 10.0.0.10"}},
+		{"srv1 | 10.0.0.10", "LBVServer", "lb_app", []string{"lb_app", "svc1", "srv1 | 10.0.0.10"}},
+	}
+	if len(routes) != len(want) {
+		t.Fatalf("Impact() returned %d routes, want %d: %+v", len(routes), len(want), routes)
+	}
+	for i, r := range routes {
+		if r.Target != want[i].Target || r.EntryType != want[i].EntryType || r.Entry != want[i].Entry || !slices.Equal(r.Route, want[i].Route) {
+			t.Errorf("route %d = %+v, want %+v", i, r, want[i])
+		}
+	}
+}
+
+func TestHighlightImpact(t *testing.T) {
+	ns := parseConfig(t, impactConfig)
+	ns.HighlightImpact(ns.Impact([]string{"srv1"}))
+
+	for _, label := range []string{"lb_other", "srv2 | 10.0.0.20", "10.0.0.2", "Global | 0.0.0.0"} {
+		if ns.getNodeByLabel(label) != nil {
+			t.Errorf("unaffected node %q kept", label)
+		}
+	}
+	for label, highlighted := range map[string]bool{"srv1 | 10.0.0.10": true, "cs_front": true, "10.0.0.1": true, "svc1": false} {
+		n := ns.getNodeByLabel(label)
+		if n == nil {
+			t.Errorf("missing node %q", label)
+			continue
+		}
+		if n.highlighted != highlighted {
+			t.Errorf("node %q highlighted = %v, want %v", label, n.highlighted, highlighted)
+		}
+	}
+	if len(ns.Edges) != 7 {
+		t.Errorf("kept %d edges, want 7", len(ns.Edges))
+	}
+}
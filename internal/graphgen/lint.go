@@ -0,0 +1,406 @@
+package graphgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// LintFinding is a single issue reported by a lint rule.
+type LintFinding struct {
+	RuleID  string `json:"ruleId"`
+	Level   string `json:"level"`
+	NSType  string `json:"nstype"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+}
+
+// LintRule checks the parsed graph for a single class of problem.
+type LintRule struct {
+	ID          string
+	Level       string
+	Description string
+	check       func(ns *NSGraph, rule LintRule) []LintFinding
+}
+
+var LintRules = []LintRule{
+	{
+		ID:          "vserver-no-services",
+		Level:       "warning",
+		Description: "LB and GSLB vservers should have at least one bound service or service group",
+		check:       lintVServerNoServices,
+	},
+	{
+		ID:          "servicegroup-no-members",
+		Level:       "warning",
+		Description: "Service groups should have at least one bound member",
+		check:       lintServiceGroupNoMembers,
+	},
+	{
+		ID:          "unbound-policy",
+		Level:       "warning",
+		Description: "Policies should be bound to a vserver, label or global bind point",
+		check:       lintUnboundPolicy,
+	},
+	{
+		ID:          "unbound-action",
+		Level:       "warning",
+		Description: "Actions should be referenced by at least one policy",
+		check:       lintUnboundAction,
+	},
+	{
+		ID:          "unreferenced-server",
+		Level:       "note",
+		Description: "Servers should be bound to a service or service group",
+		check:       lintUnreferencedServer,
+	},
+	{
+		ID:          "http-without-https-redirect",
+		Level:       "warning",
+		Description: "Addressable HTTP vservers should have a responder policy redirecting to HTTPS",
+		check:       lintHTTPWithoutRedirect,
+	},
+	{
+		ID:          "ssl-vserver-without-cert",
+		Level:       "error",
+		Description: "Addressable SSL vservers should have a bound server certificate",
+		check:       lintSSLWithoutCert,
+	},
+}
+
+// LintRuleIDs returns the IDs of every available lint rule.
+func LintRuleIDs() []string {
+	ids := []string{}
+	for _, r := range LintRules {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+// Lint runs the selected rules over the parsed graph. If enabled is empty,
+// every rule not listed in disabled is run.
+func (ns *NSGraph) Lint(enabled, disabled []string) []LintFinding {
+	findings := []LintFinding{}
+	for _, rule := range LintRules {
+		if len(enabled) > 0 && !slices.Contains(enabled, rule.ID) {
+			continue
+		}
+		if slices.Contains(disabled, rule.ID) {
+			slog.Debug("skipping disabled lint rule", "rule", rule.ID)
+			continue
+		}
+		slog.Debug("running lint rule", "rule", rule.ID)
+		findings = append(findings, rule.check(ns, rule)...)
+	}
+	slog.Info("lint complete", "findings", len(findings))
+	return findings
+}
+
+func newLintFinding(rule LintRule, n nsNode, format string, a ...any) LintFinding {
+	return LintFinding{
+		RuleID:  rule.ID,
+		Level:   rule.Level,
+		NSType:  n.nstype,
+		Name:    n.label,
+		Message: fmt.Sprintf(format, a...),
+		Line:    n.line,
+	}
+}
+
+// hasEdgeTo reports whether label has an outbound edge to a node of one of
+// the given types.
+func (ns *NSGraph) hasEdgeTo(label string, nstypes ...string) bool {
+	for _, e := range ns.getEdgesFrom(label) {
+		if to := ns.getNodeByLabel(e.to); to != nil && slices.Contains(nstypes, to.nstype) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAddressable reports whether a vserver is reached from a VIP other than
+// the non-addressable 0.0.0.0 Global node.
+func (ns *NSGraph) isAddressable(label string) bool {
+	for _, e := range ns.getEdgesTo(label) {
+		if from := ns.getNodeByLabel(e.from); from != nil && from.nstype == "VIP" && from.ip != "0.0.0.0" {
+			return true
+		}
+	}
+	return false
+}
+
+func lintVServerNoServices(ns *NSGraph, rule LintRule) []LintFinding {
+	findings := []LintFinding{}
+	for _, n := range ns.Nodes {
+		switch n.nstype {
+		case "LBVServer":
+			if !ns.hasEdgeTo(n.label, "Service", "ServiceGroup") {
+				findings = append(findings, newLintFinding(rule, n, "lb vserver %s has no bound services or service groups", n.name))
+			}
+		case "GSLBVServer":
+			if !ns.hasEdgeTo(n.label, "GSLBService", "GSLBGroup") {
+				findings = append(findings, newLintFinding(rule, n, "gslb vserver %s has no bound gslb services", n.name))
+			}
+		}
+	}
+	return findings
+}
+
+func lintServiceGroupNoMembers(ns *NSGraph, rule LintRule) []LintFinding {
+	findings := []LintFinding{}
+	for _, n := range ns.Nodes {
+		if n.nstype == "ServiceGroup" && !ns.hasEdgeTo(n.label, "Server", "Unknown", "VIP") {
+			findings = append(findings, newLintFinding(rule, n, "service group %s has no bound members", n.name))
+		}
+	}
+	return findings
+}
+
+func lintUnboundPolicy(ns *NSGraph, rule LintRule) []LintFinding {
+	findings := []LintFinding{}
+	for _, n := range ns.Nodes {
+		if !strings.HasSuffix(n.nstype, "Policy") {
+			continue
+		}
+		bound := false
+		for _, e := range ns.getEdgesTo(n.label) {
+			// content switching rules draw DomainName and URLPath edges into the policy
+			if from := ns.getNodeByLabel(e.from); from != nil && from.nstype != "DomainName" && from.nstype != "URLPath" {
+				bound = true
+				break
+			}
+		}
+		if !bound {
+			findings = append(findings, newLintFinding(rule, n, "policy %s is never bound", n.name))
+		}
+	}
+	return findings
+}
+
+func lintUnboundAction(ns *NSGraph, rule LintRule) []LintFinding {
+	findings := []LintFinding{}
+	for _, n := range ns.Nodes {
+		if strings.HasSuffix(n.nstype, "Action") && len(ns.getEdgesTo(n.label)) < 1 {
+			findings = append(findings, newLintFinding(rule, n, "action %s is not used by any policy", n.name))
+		}
+	}
+	return findings
+}
+
+func lintUnreferencedServer(ns *NSGraph, rule LintRule) []LintFinding {
+	findings := []LintFinding{}
+	for _, n := range ns.Nodes {
+		if n.nstype == "Server" && len(ns.getEdgesTo(n.label)) < 1 {
+			findings = append(findings, newLintFinding(rule, n, "server %s is not referenced by any service or service group", n.label))
+		}
+	}
+	return findings
+}
+
+func lintHTTPWithoutRedirect(ns *NSGraph, rule LintRule) []LintFinding {
+	findings := []LintFinding{}
+	// a global policy only counts if it is itself an https redirect
+	global := ns.redirectsToHTTPS(makeNodeLabel("Global", "0.0.0.0"))
+	for _, n := range ns.Nodes {
+		if n.nstype != "LBVServer" && n.nstype != "CSVServer" {
+			continue
+		}
+		if n.protocol != "HTTP" || global || !ns.isAddressable(n.label) {
+			continue
+		}
+		if !ns.redirectsToHTTPS(n.label) {
+			findings = append(findings, newLintFinding(rule, n, "http vserver %s has no responder policy redirecting to https", n.name))
+		}
+	}
+	return findings
+}
+
+// redirectsToHTTPS reports whether a responder policy bound to label, directly
+// or through an invoked policy label, uses a redirect action to an https URL.
+func (ns *NSGraph) redirectsToHTTPS(label string) bool {
+	visited := map[string]bool{label: true}
+	queue := []string{label}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range ns.getEdgesFrom(current) {
+			to := ns.getNodeByLabel(e.to)
+			if to == nil || visited[to.label] {
+				continue
+			}
+			visited[to.label] = true
+			switch to.nstype {
+			case "ResponderPolicy", "PolicyLabel":
+				queue = append(queue, to.label)
+			case "ResponderAction":
+				if ns.isHTTPSRedirect(*to) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isHTTPSRedirect reports whether a responder action redirects to an https
+// URL, either a fixed URL node or a dynamic target expression.
+func (ns *NSGraph) isHTTPSRedirect(action nsNode) bool {
+	if action.attrs["type"] != "redirect" {
+		return false
+	}
+	if isHTTPSTarget(action.attrs["target"]) {
+		return true
+	}
+	for _, e := range ns.getEdgesFrom(action.label) {
+		if to := ns.getNodeByLabel(e.to); to != nil && to.nstype == "URL" && isHTTPSTarget(to.name) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTPSTarget(target string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimLeft(target, `"`)), "https://")
+}
+
+func lintSSLWithoutCert(ns *NSGraph, rule LintRule) []LintFinding {
+	findings := []LintFinding{}
+	for _, n := range ns.Nodes {
+		if !strings.HasSuffix(n.nstype, "VServer") || n.nstype == "GSLBVServer" {
+			continue
+		}
+		if n.protocol != "SSL" && n.protocol != "SSL_TCP" {
+			continue
+		}
+		if ns.isAddressable(n.label) && !ns.hasEdgeTo(n.label, "Cert") {
+			findings = append(findings, newLintFinding(rule, n, "ssl vserver %s has no bound certificate", n.name))
+		}
+	}
+	return findings
+}
+
+// WriteLintText writes findings as one human readable line each.
+func WriteLintText(w io.Writer, inputFile string, findings []LintFinding) error {
+	for _, f := range findings {
+		location := inputFile
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", inputFile, f.Line)
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s [%s] %s\n", location, f.Level, f.RuleID, f.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteLintJSON writes findings as a JSON array.
+func WriteLintJSON(w io.Writer, findings []LintFinding) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	DefaultConfig    sarifConfig  `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteLintSARIF writes findings as a SARIF 2.1.0 log.
+func WriteLintSARIF(w io.Writer, inputFile string, findings []LintFinding) error {
+	rules := []sarifRule{}
+	for _, r := range LintRules {
+		rules = append(rules, sarifRule{
+			ID:               r.ID,
+			ShortDescription: sarifMessage{Text: r.Description},
+			DefaultConfig:    sarifConfig{Level: r.Level},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, f := range findings {
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: inputFile}}
+		if f.Line > 0 {
+			location.Region = &sarifRegion{StartLine: f.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    f.RuleID,
+			Level:     f.Level,
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "nsgraphgen",
+				InformationURI: "https://github.com/littletoyrobots/nsgraphgen",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -0,0 +1,102 @@
+package graphgen
+
+import (
+	"slices"
+	"testing"
+)
+
+func lintNames(ns *NSGraph, rule string) []string {
+	names := []string{}
+	for _, f := range ns.Lint([]string{rule}, []string{}) {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestLintHTTPWithoutRedirect(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		flagged bool
+	}{
+		{
+			name: "no responder policy",
+			config: `add lb vserver lb1 HTTP 10.0.0.1 80
+`,
+			flagged: true,
+		},
+		{
+			name: "deny responder only",
+			config: `add lb vserver lb1 HTTP 10.0.0.1 80
+add responder policy pol_deny "HTTP.REQ.URL.CONTAINS(\"admin\")" DROP
+bind lb vserver lb1 -policyName pol_deny -priority 100 -gotoPriorityExpression END -type REQUEST
+`,
+			flagged: true,
+		},
+		{
+			name: "redirect to http",
+			config: `add lb vserver lb1 HTTP 10.0.0.1 80
+add responder action act_http redirect "\"http://www.example.com/\"" -responseStatusCode 302
+add responder policy pol_http true act_http
+bind lb vserver lb1 -policyName pol_http -priority 100 -gotoPriorityExpression END -type REQUEST
+`,
+			flagged: true,
+		},
+		{
+			name: "unrelated global responder",
+			config: `add lb vserver lb1 HTTP 10.0.0.1 80
+add responder policy pol_deny "HTTP.REQ.URL.CONTAINS(\"admin\")" DROP
+bind responder global pol_deny 100 END -type REQ_DEFAULT
+`,
+			flagged: true,
+		},
+		{
+			name: "dynamic https redirect",
+			config: `add lb vserver lb1 HTTP 10.0.0.1 80
+add responder action act_https redirect "\"https://\" + HTTP.REQ.HOSTNAME + HTTP.REQ.URL.PATH_AND_QUERY" -responseStatusCode 302
+add responder policy pol_https HTTP.REQ.IS_VALID act_https
+bind lb vserver lb1 -policyName pol_https -priority 100 -gotoPriorityExpression END -type REQUEST
+`,
+			flagged: false,
+		},
+		{
+			name: "fixed https redirect",
+			config: `add lb vserver lb1 HTTP 10.0.0.1 80
+add responder action act_https redirect "\"https://www.example.com/\"" -responseStatusCode 301
+add responder policy pol_https true act_https
+bind lb vserver lb1 -policyName pol_https -priority 100 -gotoPriorityExpression END -type REQUEST
+`,
+			flagged: false,
+		},
+		{
+			name: "https redirect through policy label",
+			config: `add lb vserver lb1 HTTP 10.0.0.1 80
+add responder action act_https redirect "\"https://\" + HTTP.REQ.HOSTNAME" -responseStatusCode 302
+add responder policy pol_https true act_https
+add responder policy pol_invoke true NOOP
+add responder policylabel pl_redirect -policylabeltype HTTP
+bind responder policylabel pl_redirect pol_https 100 END
+bind lb vserver lb1 -policyName pol_invoke -priority 100 -gotoPriorityExpression END -type REQUEST -invoke policylabel pl_redirect
+`,
+			flagged: false,
+		},
+		{
+			name: "global https redirect",
+			config: `add lb vserver lb1 HTTP 10.0.0.1 80
+add responder action act_https redirect "\"https://\" + HTTP.REQ.HOSTNAME" -responseStatusCode 302
+add responder policy pol_https true act_https
+bind responder global pol_https 100 END -type REQ_DEFAULT
+`,
+			flagged: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := parseConfig(t, tt.config)
+			got := slices.Contains(lintNames(ns, "http-without-https-redirect"), "lb1")
+			if got != tt.flagged {
+				t.Errorf("lb1 flagged = %v, want %v (findings %v)", got, tt.flagged, lintNames(ns, "http-without-https-redirect"))
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/emicklei/dot"
 )
@@ -34,35 +35,87 @@ var mermaidEdgeAttrs = []mermaidEdgeAttribute{
 	{value: "SAML", color: "green"},
 	{value: "53", color: "hotpink"}, // DNS
 	{value: "DNS", color: "hotpink"},
+	{value: "ADNS", color: "hotpink"},
+	{value: "ADNS_TCP", color: "hotpink"},
+	{value: "A", color: "hotpink"},
+	{value: "AAAA", color: "hotpink"},
+	{value: "CNAME", color: "hotpink"},
+	{value: "LDAPS", color: "navy"}, // matched before the port, e.g. LDAP_TLS on 389
+	{value: "LDAP_TLS", color: "navy"},
 	{value: "389", color: "orange"}, // LDAP
 	{value: "LDAP", color: "orange"},
 	{value: "636", color: "navy"},     // LDAPS
 	{value: "1812", color: "magenta"}, // RADIUS
 	{value: "RADIUS", color: "magenta"},
 	{value: "CERT", color: "greenyellow"},
+	{value: "SNICERT", color: "yellowgreen"},
+	{value: "CACERT", color: "mediumseagreen"},
+	{value: "CHAIN", color: "darkgreen"},
+	{value: "SSLPROFILE", color: "darkseagreen"},
+	{value: "CIPHER", color: "darkseagreen"},
 	{value: "STA", color: "cadetblue"},
 	{value: "BASETHEME", color: "black"},
 	{value: "LOGINSCHEMA", color: "violet"},
 	{value: "NFACTOR", color: "pink"},
+	{value: "AUTHN", color: "lightcoral"},
+	{value: "AUTHNPROFILE", color: "lightcoral"},
+	{value: "MONITOR", color: "steelblue"},
+	{value: "INVOKE", color: "purple"},
+	{value: "REDIRECT", color: "chocolate"},
 }
 
 var mermaidNodeAttrs = []mermaidNodeAttribute{
 	{nstype: "Unknown", shape: "stadium", style: "fill:#ff00ff"},
+	{nstype: "AAAGroup", shape: "stadium", style: "fill:#ffcc99"},
+	{nstype: "AAAUser", shape: "round", style: "fill:#ffcc99"},
+	{nstype: "AppFWPolicy", shape: "trapezoid", style: "fill:#cd5c5c"},
+	{nstype: "AppFWProfile", shape: "trapezoid-alt", style: "fill:#cd5c5c"},
 	{nstype: "AuthAction", shape: "trapezoid-alt", style: "fill:#ffcc99"},
+	{nstype: "AuthnProfile", shape: "box", style: "fill:#ffcc99"},
+	{nstype: "AuthorizationPolicy", shape: "trapezoid", style: "fill:#ffcc99"},
 	{nstype: "AuthPolicy", shape: "trapezoid", style: "fill:#ffcc99"},
 	{nstype: "AuthVServer", shape: "hexagon", style: "fill:#ffcc99"},
+	{nstype: "BotPolicy", shape: "trapezoid", style: "fill:#bc8f8f"},
+	{nstype: "BotProfile", shape: "trapezoid-alt", style: "fill:#bc8f8f"},
+	{nstype: "CacheContentGroup", shape: "box", style: "fill:#f0e68c"},
+	{nstype: "CachePolicy", shape: "trapezoid", style: "fill:#f0e68c"},
+	{nstype: "CACert", shape: "asymmetric", style: "fill:#3cb371"},
 	{nstype: "Cert", shape: "asymmetric", style: "fill:#00ff00"},
+	{nstype: "CipherGroup", shape: "subroutine", style: "fill:#8fbc8f"},
+	{nstype: "ClientlessAccessPolicy", shape: "trapezoid", style: "fill:#33ccff"},
+	{nstype: "ClientlessAccessProfile", shape: "box", style: "fill:#33ccff"},
+	{nstype: "CMPAction", shape: "trapezoid-alt", style: "fill:#e0ffff"},
+	{nstype: "CMPPolicy", shape: "trapezoid", style: "fill:#e0ffff"},
 	{nstype: "CSAction", shape: "trapezoid-alt", style: "fill:#ffb366"},
 	{nstype: "CSPolicy", shape: "trapezoid", style: "fill:#ffb366"},
 	{nstype: "CSVServer", shape: "hexagon", style: "fill:#ffb366"},
+	{nstype: "DataSet", shape: "cylinder", style: "fill:#f5deb3"},
+	{nstype: "DNSRecord", shape: "box", style: "fill:#ff69b4"},
+	{nstype: "DNSView", shape: "stadium", style: "fill:#ff69b4"},
+	{nstype: "DNSZone", shape: "subroutine", style: "fill:#ff69b4"},
 	{nstype: "DomainName", shape: "stadium", style: "fill:#ff00ff"},
+	{nstype: "FEOAction", shape: "trapezoid-alt", style: "fill:#f0fff0"},
+	{nstype: "FEOPolicy", shape: "trapezoid", style: "fill:#f0fff0"},
 	{nstype: "GSLBService", shape: "box", style: "fill:#66ccff"},
 	{nstype: "GSLBGroup", shape: "box", style: "fill:#66ccff"},
 	{nstype: "GSLBVServer", shape: "hexagon", style: "fill:#66ccff"},
+	{nstype: "HTMLPage", shape: "box", style: "fill:#ffffcc"},
+	{nstype: "HTTPCallout", shape: "asymmetric", style: "fill:#dda0dd"},
+	{nstype: "ICAAccessProfile", shape: "box", style: "fill:#48d1cc"},
+	{nstype: "ICAAction", shape: "trapezoid-alt", style: "fill:#48d1cc"},
+	{nstype: "ICALatencyProfile", shape: "box", style: "fill:#48d1cc"},
+	{nstype: "ICAPolicy", shape: "trapezoid", style: "fill:#48d1cc"},
+	{nstype: "IntranetApp", shape: "subroutine", style: "fill:#afeeee"},
 	{nstype: "LBGroup", shape: "stadium", style: "fill:#ffff99"},
 	{nstype: "LBVServer", shape: "hexagon", style: "fill:#ffff99"},
+	{nstype: "LoginSchema", shape: "box", style: "fill:#ee82ee"},
+	{nstype: "LoginSchemaPolicy", shape: "trapezoid", style: "fill:#ee82ee"},
+	{nstype: "Monitor", shape: "subroutine", style: "fill:#b0c4de"},
+	{nstype: "NameServer", shape: "round", style: "fill:#ff69b4"},
 	{nstype: "Netscaler", shape: "circle", style: "fill:#00ffff"},
+	{nstype: "PatSet", shape: "cylinder", style: "fill:#f5deb3"},
 	{nstype: "Policy", shape: "folder", style: "fill:#ff99ff"},
+	{nstype: "PolicyExpression", shape: "box", style: "fill:#e6e6fa"},
 	{nstype: "PolicyLabel", shape: "tab", style: "fill:#ff99ff"},
 	{nstype: "PortalTheme", shape: "note", style: "fill:#33ccff"},
 	{nstype: "ResponderAction", shape: "trapezoid-alt", style: "fill:#ffffcc"},
@@ -74,7 +127,17 @@ var mermaidNodeAttrs = []mermaidNodeAttribute{
 	{nstype: "ServiceGroup", shape: "box", style: "fill:#e6e6e6"},
 	{nstype: "SessionAction", shape: "trapezoid-alt", style: "fill:#00ff99"},
 	{nstype: "SessionPolicy", shape: "trapezoid", style: "fill:#00ff99"},
+	{nstype: "SSLProfile", shape: "box", style: "fill:#8fbc8f"},
 	{nstype: "STA", shape: "box", style: "fill:#66ccff"},
+	{nstype: "StringMap", shape: "cylinder", style: "fill:#f5deb3"},
+	{nstype: "TrafficAction", shape: "trapezoid-alt", style: "fill:#00ff99"},
+	{nstype: "TrafficPolicy", shape: "trapezoid", style: "fill:#00ff99"},
+	{nstype: "TransformAction", shape: "trapezoid-alt", style: "fill:#d8bfd8"},
+	{nstype: "TransformPolicy", shape: "trapezoid", style: "fill:#d8bfd8"},
+	{nstype: "TransformProfile", shape: "box", style: "fill:#d8bfd8"},
+	{nstype: "URL", shape: "asymmetric", style: "fill:#afeeee"},
+	{nstype: "URLPath", shape: "stadium", style: "fill:#afeeee"},
+	{nstype: "VPNURL", shape: "asymmetric", style: "fill:#afeeee"},
 	{nstype: "VPNVServer", shape: "hexagon", style: "fill:#33ccff"},
 	{nstype: "WI", shape: "hexagon", style: "fill:#33ccff"},
 	{nstype: "VIP", shape: "circle", style: "fill:#ffff00"},
@@ -85,18 +148,39 @@ var mermaidHighlightColor = "magenta"
 func (ns *NSGraph) ExportMermaid(outputFile string, stdout bool) {
 	ns.Graph = dot.NewGraph(dot.Directed)
 
+	clusters := map[string]string{}
 	for _, v := range ns.Nodes {
 
 		attr := getMermaidNodeAttribute(v.nstype)
 		style := ""
 		// style := attr.style
+		if v.color != "" {
+			attr.style = fmt.Sprintf("fill:%s", v.color)
+			style = attr.style
+		}
 		if v.highlighted {
 			style = fmt.Sprintf("%s,stroke:%s", attr.style, mermaidHighlightColor)
 		}
-		ns.Graph.Node(v.label).Attr("shape", attr.shape).Attr("style", style)
+		graph := ns.Graph
+		if v.cluster != "" {
+			// mermaid subgraph ids cannot contain spaces
+			if _, ok := clusters[v.cluster]; !ok {
+				clusters[v.cluster] = fmt.Sprintf("cluster%d", len(clusters)+1)
+			}
+			graph = ns.Graph.Subgraph(clusters[v.cluster])
+			graph.Attr("label", v.cluster)
+		}
+		node := graph.Node(v.label).Attr("shape", attr.shape).Attr("style", style)
+		if shown := nodeLabelAttrs(v); len(shown) > 0 {
+			// mermaid has no tooltips, so key attributes go in the label
+			node.Label(fmt.Sprintf("%s [%s]", v.label, strings.Join(shown, ", ")))
+		}
 	}
-	for _, v := range ns.Edges {
+	for _, v := range ns.orderedEdges() {
 		attr := getMermaidEdgeAttribute(v.port, v.protocol)
+		if v.color != "" {
+			attr.color = v.color
+		}
 		from, found_from := ns.Graph.FindNodeById(v.from)
 		if !found_from {
 			slog.Debug("from node not found, skipping", "label", v.from)
@@ -0,0 +1,39 @@
+package graphgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportMermaidLabelAttrs(t *testing.T) {
+	ns := parseConfig(t, gslbConfig)
+	out := filepath.Join(t.TempDir(), "out.mmd")
+	ns.ExportMermaid(out, false)
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"gvs_app [lbMethod=RTT, persistenceType=SOURCEIP]"`) {
+		t.Errorf("gslb vserver label missing lbMethod and persistenceType:\n%s", b)
+	}
+	if !strings.Contains(string(b), "subgraph cluster1 [site_east]") {
+		t.Errorf("gslb site subgraph missing:\n%s", b)
+	}
+}
+
+func TestExportMermaidPriorityOrder(t *testing.T) {
+	ns := parseConfig(t, priorityConfig)
+	out := filepath.Join(t.TempDir(), "out.mmd")
+	ns.ExportMermaid(out, false)
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := []string{}
+	for _, l := range priorityLabels {
+		labels = append(labels, "|"+l+"|")
+	}
+	wantInOrder(t, string(b), labels)
+}
@@ -0,0 +1,89 @@
+package graphgen
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// nfactorSkipTypes are bound to AAA vservers but play no part in the
+// authentication flow.
+var nfactorSkipTypes = []string{
+	"CACert",
+	"Cert",
+	"CipherGroup",
+	"PortalTheme",
+	"SSLProfile",
+}
+
+// NFactorFlow is the authentication flow of a single AAA vserver, with each
+// node clustered by the factor it is evaluated in.
+type NFactorFlow struct {
+	VServer string
+	Factors int
+	Graph   *NSGraph
+}
+
+// NFactorFlows walks each AAA vserver's bound policies, policy labels and
+// login schemas, following -nextFactor edges to number the factors.
+func (ns *NSGraph) NFactorFlows() []NFactorFlow {
+	flows := []NFactorFlow{}
+	for _, n := range ns.Nodes {
+		if n.nstype != "AuthVServer" {
+			continue
+		}
+		flow := ns.nfactorFlow(n)
+		slog.Debug("nfactor flow", "vserver", flow.VServer, "factors", flow.Factors)
+		flows = append(flows, flow)
+	}
+	slog.Info("nfactor search complete", "vservers", len(flows))
+	return flows
+}
+
+func (ns *NSGraph) nfactorFlow(vserver nsNode) NFactorFlow {
+	sub := New("LR", []string{}, []string{}, []string{})
+	factors := map[string]int{vserver.label: 1}
+	queue := []string{vserver.label}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range ns.getEdgesFrom(current) {
+			to := ns.getNodeByLabel(e.to)
+			if to == nil || slices.Contains(nfactorSkipTypes, to.nstype) {
+				continue
+			}
+			if next := e.attrs["gotoPriorityExpression"]; next != "" {
+				// show whether evaluation continues within the factor
+				e.label = makeBindLabel(makeEdgeLabel(e.port, joinLabel(e.protocol, next)), e.attrs)
+			}
+			sub.Edges = append(sub.Edges, e)
+			if _, ok := factors[to.label]; ok {
+				continue
+			}
+			factors[to.label] = factors[current]
+			if e.protocol == "NFACTOR" && to.nstype == "PolicyLabel" {
+				factors[to.label]++
+			}
+			queue = append(queue, to.label)
+		}
+	}
+
+	flow := NFactorFlow{VServer: vserver.name, Graph: sub}
+	for _, n := range ns.Nodes {
+		factor, ok := factors[n.label]
+		if !ok {
+			continue
+		}
+		n.cluster = fmt.Sprintf("Factor %d", factor)
+		sub.Nodes = append(sub.Nodes, n)
+		flow.Factors = max(flow.Factors, factor)
+	}
+	return flow
+}
+
+func joinLabel(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " | " + b
+}
@@ -0,0 +1,66 @@
+package graphgen
+
+import "testing"
+
+const nfactorConfig = `add authentication vserver aaa_vs SSL 10.0.0.9 443
+add authentication ldapAction ldap1 -serverIP 10.0.0.5
+add authentication radiusAction radius1 -serverIP 10.0.0.6 -serverPort 1812
+add authentication loginSchema ls_otp -authenticationSchema "/nsconfig/loginschema/otp.xml"
+add authentication Policy pol_ldap -rule true -action ldap1
+add authentication Policy pol_otp -rule true -action radius1
+add authentication policylabel pl_otp -loginSchema ls_otp
+bind authentication vserver aaa_vs -policy pol_ldap -priority 100 -nextFactor pl_otp -gotoPriorityExpression NEXT
+bind authentication policylabel pl_otp -policyName pol_otp -priority 10 -gotoPriorityExpression END
+bind ssl vserver aaa_vs -certkeyName cert_aaa
+`
+
+func TestNFactorFlows(t *testing.T) {
+	ns := parseConfig(t, nfactorConfig)
+	flows := ns.NFactorFlows()
+	if len(flows) != 1 {
+		t.Fatalf("NFactorFlows() returned %d flows, want 1", len(flows))
+	}
+	flow := flows[0]
+	if flow.VServer != "aaa_vs" || flow.Factors != 2 {
+		t.Errorf("flow = %s with %d factors, want aaa_vs with 2", flow.VServer, flow.Factors)
+	}
+
+	clusters := map[string]string{
+		"aaa_vs":   "Factor 1",
+		"pol_ldap": "Factor 1",
+		"ldap1":    "Factor 1",
+		"pl_otp":   "Factor 2",
+		"ls_otp":   "Factor 2",
+		"pol_otp":  "Factor 2",
+		"radius1":  "Factor 2",
+	}
+	for name, cluster := range clusters {
+		n := findNode(flow.Graph, name)
+		if n == nil {
+			t.Errorf("flow missing node %q", name)
+			continue
+		}
+		if n.cluster != cluster {
+			t.Errorf("node %q cluster = %q, want %q", name, n.cluster, cluster)
+		}
+	}
+	if n := findNode(flow.Graph, "cert_aaa"); n != nil {
+		t.Errorf("flow includes %s %q", n.nstype, n.name)
+	}
+
+	labels := map[[2]string]string{
+		{"aaa_vs", "pol_ldap"}: "#100 | NFACTOR | NEXT",
+		{"pol_ldap", "pl_otp"}: "NFACTOR",
+		{"pl_otp", "pol_otp"}:  "#10 | END",
+	}
+	for edge, label := range labels {
+		e := findEdge(flow.Graph, edge[0], edge[1])
+		if e == nil {
+			t.Errorf("flow missing edge %q -> %q", edge[0], edge[1])
+			continue
+		}
+		if e.label != label {
+			t.Errorf("edge %q -> %q label = %q, want %q", edge[0], edge[1], e.label, label)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package graphgen
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// orphanRootTypes are never reported as orphans; everything reachable from
+// them is in use.
+var orphanRootTypes = []string{
+	"AAAGroup",
+	"AAAUser",
+	"DNSZone",
+	"DomainName",
+	"Netscaler",
+	"URL",
+	"URLPath",
+	"VIP",
+}
+
+// builtinNames are objects every appliance ships with. They are never
+// reported, even when nothing in the config uses them.
+var builtinNames = []string{
+	// portal themes
+	"Default", "Greenbubble", "X1", "RfWebUI",
+	// cipher groups
+	"ALL", "DEFAULT", "DEFAULT_BACKEND", "DEFAULT_ECC", "HIGH", "MEDIUM", "LOW", "EXPORT",
+	// monitors
+	"ping-default", "tcp-default", "arp", "nd6", "ping", "tcp", "http", "tcp-ecv", "http-ecv",
+	"udp-ecv", "dns", "ftp", "tcps", "https", "tcps-ecv", "https-ecv", "xdm", "xnc",
+	"mqtt", "mqtt-tls", "http2direct", "http2ssl", "ldns-ping", "ldns-tcp", "ldns-dns",
+	"sta", "stasecure",
+	// actions
+	"NOOP", "RESET", "DROP", "NOREWRITE", "COMPRESS", "GZIP", "DEFLATE", "NOCOMPRESS",
+	"CACHE", "NOCACHE", "MAY_CACHE", "MAY_NOCACHE", "INVAL",
+	// cache content groups and login schemas
+	"BASEFILE", "DELTAJS", "LSCHEMA_INT",
+}
+
+// isBuiltin reports whether name is a built-in object, including the ns_
+// defaults such as ns_default_ssl_profile_frontend and the _-prefixed
+// objects the appliance binds internally.
+func isBuiltin(name string) bool {
+	return slices.Contains(builtinNames, name) || strings.HasPrefix(name, "ns_") || strings.HasPrefix(name, "_")
+}
+
+// Orphan is an object with no inbound references from any vserver path.
+type Orphan struct {
+	NSType  string
+	Name    string
+	Line    int
+	Command string // rm command to remove it, empty if unknown
+}
+
+func isOrphanRoot(nstype string) bool {
+	return slices.Contains(orphanRootTypes, nstype) || strings.HasSuffix(nstype, "VServer")
+}
+
+// Orphans returns every object that cannot be reached from a VIP or vserver,
+// ordered so that each object is listed before anything it references.
+func (ns *NSGraph) Orphans() []Orphan {
+	reachable := map[string]bool{}
+	queue := []string{}
+	for _, n := range ns.Nodes {
+		if isOrphanRoot(n.nstype) {
+			reachable[n.label] = true
+			queue = append(queue, n.label)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range ns.getEdgesFrom(current) {
+			if !reachable[e.to] {
+				reachable[e.to] = true
+				queue = append(queue, e.to)
+			}
+		}
+	}
+
+	pending := []nsNode{}
+	for _, n := range ns.Nodes {
+		if !reachable[n.label] && !isBuiltin(n.name) {
+			pending = append(pending, n)
+		}
+	}
+
+	// remove referencing objects before the objects they reference
+	orphans := []Orphan{}
+	for len(pending) > 0 {
+		next := []nsNode{}
+		ready := []nsNode{}
+		for _, n := range pending {
+			referenced := false
+			for _, e := range ns.getEdgesTo(n.label) {
+				if slices.ContainsFunc(pending, func(p nsNode) bool { return p.label == e.from && p.label != n.label }) {
+					referenced = true
+					break
+				}
+			}
+			if referenced {
+				next = append(next, n)
+			} else {
+				ready = append(ready, n)
+			}
+		}
+		if len(ready) < 1 {
+			// reference cycle, remove the rest in config order
+			ready, next = next, nil
+		}
+		for _, n := range ready {
+			orphans = append(orphans, Orphan{
+				NSType:  n.nstype,
+				Name:    n.label,
+				Line:    n.line,
+				Command: ns.removeCommand(n),
+			})
+		}
+		pending = next
+	}
+	slog.Info("orphan search complete", "orphans", len(orphans))
+	return orphans
+}
+
+func (ns *NSGraph) removeCommand(n nsNode) string {
+	if n.line < 1 || n.line > len(ns.lines) {
+		return ""
+	}
+	cmd := definitionCommand(ns.lines[n.line-1], n.name)
+	if cmd == "" {
+		return ""
+	}
+	name := n.name
+	if strings.ContainsAny(name, " \t") {
+		name = fmt.Sprintf("%q", name)
+	}
+	if n.nstype == "Monitor" && n.attrs["type"] != "" {
+		// monitors are removed by name and type
+		return fmt.Sprintf("rm %s %s %s", cmd, name, n.attrs["type"])
+	}
+	return fmt.Sprintf("rm %s %s", cmd, name)
+}
+
+// KeepOrphans reduces the graph to orphaned objects and the edges between
+// them.
+func (ns *NSGraph) KeepOrphans() {
+	slog.Info("pruning non-orphaned nodes")
+	orphans := map[string]bool{}
+	for _, o := range ns.Orphans() {
+		orphans[o.Name] = true
+	}
+
+	newNodes := []nsNode{}
+	for _, n := range ns.Nodes {
+		if orphans[n.label] {
+			newNodes = append(newNodes, n)
+		}
+	}
+	newEdges := []nsEdge{}
+	for _, e := range ns.Edges {
+		if orphans[e.from] && orphans[e.to] {
+			newEdges = append(newEdges, e)
+		}
+	}
+	ns.Nodes = newNodes
+	ns.Edges = newEdges
+}
+
+// WriteOrphanReport writes orphans grouped by nstype.
+func WriteOrphanReport(w io.Writer, orphans []Orphan) error {
+	for _, nstype := range NodeTypes {
+		group := []Orphan{}
+		for _, o := range orphans {
+			if o.NSType == nstype {
+				group = append(group, o)
+			}
+		}
+		if len(group) < 1 {
+			continue
+		}
+		slices.SortFunc(group, func(a, b Orphan) int { return strings.Compare(a.Name, b.Name) })
+		if _, err := fmt.Fprintf(w, "%s (%d)\n", nstype, len(group)); err != nil {
+			return err
+		}
+		for _, o := range group {
+			if _, err := fmt.Fprintf(w, "  %-40s line %d\n", o.Name, o.Line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteOrphanScript writes the rm commands needed to remove the orphans.
+// Every command is commented out, so nothing runs until it has been reviewed
+// and uncommented.
+func WriteOrphanScript(w io.Writer, inputFile string, orphans []Orphan) error {
+	header := fmt.Sprintf("# orphaned objects found in %s by nsgraphgen\n", inputFile) +
+		"# review each command and remove the leading # before running it\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	for _, o := range orphans {
+		line := "# " + o.Command
+		if o.Command == "" {
+			line = fmt.Sprintf("# no rm command found for %s %s", o.NSType, o.Name)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package graphgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parseConfig writes config to a temporary ns.conf and parses it.
+func parseConfig(t *testing.T, config string) *NSGraph {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ns.conf")
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ns := New("LR", []string{}, []string{}, []string{})
+	if err := ns.Parse(path); err != nil {
+		t.Fatal(err)
+	}
+	return ns
+}
+
+const orphanConfig = `add server srv1 10.0.0.10
+add server srv_unused 10.0.0.99
+add service svc1 srv1 HTTP 80
+add lb vserver lb1 HTTP 10.0.0.1 80
+add vpn portaltheme theme1 -basetheme RfWebUI
+add responder policy pol_unused true act_missing
+bind lb vserver lb1 svc1
+bind vpn global -portaltheme theme1
+`
+
+func TestOrphans(t *testing.T) {
+	ns := parseConfig(t, orphanConfig)
+	got := map[string]Orphan{}
+	for _, o := range ns.Orphans() {
+		got[o.Name] = o
+	}
+
+	for _, name := range []string{"srv_unused | 10.0.0.99", "pol_unused", "act_missing"} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("Orphans() missing %q", name)
+		}
+	}
+	for _, name := range []string{"srv1 | 10.0.0.10", "svc1", "lb1", "theme1", "RfWebUI"} {
+		if _, ok := got[name]; ok {
+			t.Errorf("Orphans() reported %q, which is in use or built in", name)
+		}
+	}
+	if cmd := got["pol_unused"].Command; cmd != "rm responder policy pol_unused" {
+		t.Errorf("pol_unused command = %q", cmd)
+	}
+}
+
+func TestWriteOrphanScript(t *testing.T) {
+	orphans := []Orphan{
+		{NSType: "Server", Name: "srv_unused | 10.0.0.99", Command: "rm server srv_unused"},
+		{NSType: "Unknown", Name: "act_missing"},
+	}
+	var b strings.Builder
+	if err := WriteOrphanScript(&b, "ns.conf", orphans); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			t.Errorf("script line %q is not commented out", line)
+		}
+	}
+	if !strings.Contains(b.String(), "# rm server srv_unused\n") {
+		t.Errorf("script missing commented rm command:\n%s", b.String())
+	}
+}
+
+const dnsOrphanConfig = `add lb vserver lb_app HTTP 10.0.0.1 80
+add service svc_adns 10.0.0.53 ADNS 53
+add dns soaRec corp.example -originServer ns1.corp.example -contact admin.corp.example
+add dns nsRec corp.example ns1.corp.example
+add dns addRec app.corp.example 10.0.0.1
+add dns addRec stale.other.test 10.0.0.77
+add dns cnameRec www.corp.example app.corp.example
+add dns zone corp.example -proxyMode NO
+`
+
+func TestOrphansDNS(t *testing.T) {
+	ns := parseConfig(t, dnsOrphanConfig)
+	got := map[string]Orphan{}
+	for _, o := range ns.Orphans() {
+		got[o.Name] = o
+	}
+
+	for _, name := range []string{"corp.example", "app.corp.example", "www.corp.example", "ns1.corp.example"} {
+		if _, ok := got[name]; ok {
+			t.Errorf("Orphans() reported %q, which is served by a zone", name)
+		}
+	}
+	if o, ok := got["stale.other.test"]; !ok || o.Command != "rm dns addRec stale.other.test" {
+		t.Errorf("Orphans() stale.other.test = %+v", o)
+	}
+
+	// name servers are added after the scan, but point at their record
+	if n := findNode(ns, "ns1.corp.example"); n == nil || n.line != 3 {
+		t.Errorf("name server node = %+v, want line 3", n)
+	}
+}
@@ -1,12 +1,139 @@
 package graphgen
 
 import (
+	"net/url"
 	"regexp"
 	"slices"
 	"strings"
 )
 
-func (ns *NSGraph) parseNSline(line string) {
+var globalBindRegex = regexp.MustCompile(`^bind \S+ (global|\S+Global) `)
+
+// termValue returns the value following option, e.g. "-interval", or an
+// empty string if the option is not present.
+func termValue(term []string, option string) string {
+	idx := slices.Index(term, option)
+	if idx == -1 || idx+1 >= len(term) {
+		return ""
+	}
+	return term[idx+1]
+}
+
+// bindAttrs collects the evaluation order options of a policy binding.
+func bindAttrs(term []string) map[string]string {
+	attrs := map[string]string{
+		"priority":               termValue(term, "-priority"),
+		"gotoPriorityExpression": termValue(term, "-gotoPriorityExpression"),
+		"type":                   termValue(term, "-type"),
+	}
+	idx := slices.Index(term, "-invoke")
+	if idx != -1 && idx+2 < len(term) {
+		attrs["invoke"] = term[idx+1] + " " + term[idx+2]
+	}
+	return attrs
+}
+
+// positionalBindAttrs collects binding options for commands that take the
+// priority and goto expression as positional arguments, e.g.
+// bind rewrite policylabel <label> <policy> <priority> [<goto>].
+func positionalBindAttrs(term []string, priorityIdx int) map[string]string {
+	attrs := bindAttrs(term)
+	if priorityIdx >= len(term) || strings.HasPrefix(term[priorityIdx], "-") {
+		return attrs
+	}
+	attrs["priority"] = term[priorityIdx]
+	if priorityIdx+1 < len(term) && !strings.HasPrefix(term[priorityIdx+1], "-") {
+		attrs["gotoPriorityExpression"] = term[priorityIdx+1]
+	}
+	return attrs
+}
+
+// addInvokeEdge links the bind point owner to the policy label or vserver
+// named by the binding's -invoke option. The same policy can be bound in
+// several places with different invokes, so the edge starts at the owner
+// and records the invoking policy and its priority.
+func (ns *NSGraph) addInvokeEdge(owner, policy string, term []string) {
+	idx := slices.Index(term, "-invoke")
+	if idx == -1 || idx+2 >= len(term) {
+		return
+	}
+	labelType := term[idx+1]
+	target := term[idx+2]
+	if strings.EqualFold(labelType, "policylabel") {
+		ns.addNode("PolicyLabel", target, "", "", "")
+	}
+	attrs := map[string]string{
+		"policy":   policy,
+		"priority": termValue(term, "-priority"),
+	}
+	ns.addEdgeAttrs(owner, target, "", "INVOKE", attrs)
+}
+
+// addAuthnProfileEdge links a vserver to the authentication profile named by
+// its -authnProfile option.
+func (ns *NSGraph) addAuthnProfileEdge(vserver string, term []string) {
+	profile := termValue(term, "-authnProfile")
+	if profile == "" {
+		return
+	}
+	ns.addNode("AuthnProfile", profile, "", "", "")
+	ns.addEdge(vserver, profile, "", "AUTHNPROFILE")
+}
+
+// addURLEdge links an object to the host of a URL option, such as an email
+// action's -serverURL, with the URL's port.
+func (ns *NSGraph) addURLEdge(name, rawURL, protocol string) {
+	u, err := url.Parse(exprValue(rawURL))
+	if err != nil || u.Hostname() == "" {
+		return
+	}
+	host := u.Hostname()
+	ns.addNode("Server", host, "", "", "")
+	ns.addEdge(name, host, u.Port(), protocol)
+}
+
+// addVPNResourceEdges links a VPN vserver, AAA group or user, or the VPN
+// global bind point to the intranet application or bookmark bound to it, and
+// reports whether either was found.
+func (ns *NSGraph) addVPNResourceEdges(name string, term []string) bool {
+	app := termValue(term, "-intranetApplication")
+	bookmark := termValue(term, "-urlName")
+	if app != "" {
+		ns.addNode("IntranetApp", app, "", "", "")
+		ns.addEdge(name, app, "", "INTRANETAPP")
+	}
+	if bookmark != "" {
+		ns.addNode("VPNURL", bookmark, "", "", "")
+		ns.addEdge(name, bookmark, "", "BOOKMARK")
+	}
+	return app != "" || bookmark != ""
+}
+
+// addSessionSettings records the ICA proxy and clientless settings of a VPN
+// session action or the global VPN parameters, links the StoreFront and Web
+// Interface URLs they send users to, and reports whether any were found.
+func (ns *NSGraph) addSessionSettings(name string, term []string) bool {
+	found := false
+	for _, opt := range []string{"-icaProxy", "-clientlessVpnMode", "-clientlessModeUrlEncoding", "-clientlessPersistentCookie", "-wiPortalMode", "-ntDomain", "-SSO", "-defaultAuthorizationAction"} {
+		if value := termValue(term, opt); value != "" {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), value)
+			found = true
+		}
+	}
+	if wi := termValue(term, "-wihome"); wi != "" {
+		ns.addNode("WI", wi, "", "", "")
+		ns.addEdge(name, wi, "", "wi")
+		found = true
+	}
+	if storefront := termValue(term, "-storefronturl"); storefront != "" {
+		ns.addNode("WI", storefront, "", "", "")
+		ns.addEdge(name, storefront, "", "STOREFRONT")
+		found = true
+	}
+	return found
+}
+
+func (ns *NSGraph) parseNSline(line string) bool {
 
 	// name := ""
 	// ip := ""
@@ -22,25 +149,132 @@ func (ns *NSGraph) parseNSline(line string) {
 		term[i] = strings.Trim(v, "\"")
 	}
 
-	if strings.HasPrefix(line, "add authentication ldapAction ") {
+	if strings.HasPrefix(line, "add aaa group ") {
 		name := term[3]
-		to := term[5]
-		port := term[7]
-		protocol := ""
-		switch port {
-		case "389":
-			protocol = "LDAP"
-		case "636":
+		ns.addNode("AAAGroup", name, "", "", "")
+		ns.setNodeAttr(name, "weight", termValue(term, "-weight"))
+	} else if strings.HasPrefix(line, "add aaa user ") {
+		ns.addNode("AAAUser", term[3], "", "", "")
+	} else if strings.HasPrefix(line, "add appfw policy ") {
+		// add appfw policy <name> <rule> <profileName>
+		if len(term) < 6 {
+			return false
+		}
+		name := term[3]
+		profile := term[5]
+		ns.addNode("AppFWPolicy", name, "", "", "")
+		ns.addNode("AppFWProfile", profile, "", "", "")
+		ns.addEdge(name, profile, "", "")
+	} else if strings.HasPrefix(line, "add appfw profile ") {
+		name := term[3]
+		ns.addNode("AppFWProfile", name, "", "", "")
+		ns.setNodeAttr(name, "defaults", termValue(term, "-defaults"))
+		ns.setNodeAttr(name, "type", termValue(term, "-type"))
+	} else if strings.HasPrefix(line, "add authentication captchaAction ") || strings.HasPrefix(line, "add authentication dfaAction ") || strings.HasPrefix(line, "add authentication storefrontAuthAction ") {
+		// actions that call out to a web service by -serverURL
+		name := term[3]
+		protocol := strings.ToUpper(strings.TrimSuffix(strings.TrimSuffix(term[2], "Action"), "Auth"))
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
+		ns.addURLEdge(name, termValue(term, "-serverURL"), protocol)
+	} else if strings.HasPrefix(line, "add authentication certAction ") {
+		name := term[3]
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
+		ns.setNodeAttr(name, "twoFactor", termValue(term, "-twoFactor"))
+		ns.setNodeAttr(name, "userNameField", termValue(term, "-userNameField"))
+	} else if strings.HasPrefix(line, "add authentication emailAction ") {
+		name := term[3]
+		protocol := termValue(term, "-type")
+		if protocol == "" {
+			protocol = "SMTP"
+		}
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
+		ns.addURLEdge(name, termValue(term, "-serverURL"), protocol)
+	} else if strings.HasPrefix(line, "add authentication epaAction ") {
+		// endpoint analysis scans place users in AAA groups
+		name := term[3]
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
+		ns.setNodeAttr(name, "csecexpr", exprValue(termValue(term, "-csecexpr")))
+		for _, opt := range []string{"-defaultEPAGroup", "-quarantineGroup"} {
+			if group := termValue(term, opt); group != "" {
+				ns.addNode("AAAGroup", group, "", "", "")
+				ns.addEdge(name, group, "", strings.ToUpper(strings.TrimPrefix(opt, "-")))
+			}
+		}
+	} else if strings.HasPrefix(line, "add authentication ldapAction ") {
+		name := term[3]
+		port := termValue(term, "-serverPort")
+		if port == "" {
+			port = "389"
+		}
+		protocol := "LDAP"
+		switch strings.ToUpper(termValue(term, "-secType")) {
+		case "SSL":
 			protocol = "LDAPS"
-			//	default: protocol = ""
+		case "TLS":
+			protocol = "LDAP_TLS"
+		case "":
+			if port == "636" {
+				protocol = "LDAPS"
+			}
 		}
 		ns.addNode("AuthAction", name, "", "", "")
-		ns.addEdge(name, to, port, protocol)
+		ns.setNodeAttr(name, "type", term[2])
+		for _, opt := range []string{"-secType", "-ldapBase", "-ldapBindDn", "-ldapLoginName"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), exprValue(termValue(term, opt)))
+		}
+		if ip := termValue(term, "-serverIP"); ip != "" {
+			ns.addEdge(name, ip, port, protocol)
+		}
+		if fqdn := termValue(term, "-serverName"); fqdn != "" {
+			ns.addNode("Server", fqdn, "", "", "")
+			ns.addEdge(name, fqdn, port, protocol)
+		}
+	} else if strings.HasPrefix(line, "add authentication authnProfile ") {
+		name := term[3]
+		ns.addNode("AuthnProfile", name, "", "", "")
+		ns.setNodeAttr(name, "authenticationHost", termValue(term, "-AuthenticationHost"))
+		ns.setNodeAttr(name, "authenticationDomain", termValue(term, "-AuthenticationDomain"))
+		if vserver := termValue(term, "-authnVsName"); vserver != "" {
+			ns.addNode("AuthVServer", vserver, "", "", "")
+			ns.addEdge(name, vserver, "", "AUTHN")
+		}
 	} else if strings.HasPrefix(line, "add authentication ldapPolicy") {
 		name := term[3]
 		to := term[5]
 		ns.addNode("AuthPolicy", name, "", "", "")
 		ns.addEdge(name, to, "", "")
+	} else if strings.HasPrefix(line, "add authentication loginSchema ") {
+		name := term[3]
+		ns.addNode("LoginSchema", name, "", "", "")
+		for _, opt := range []string{"-authenticationSchema", "-userExpression", "-passwdExpression", "-SSOCredentials"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), exprValue(termValue(term, opt)))
+		}
+	} else if strings.HasPrefix(line, "add authentication loginSchemaPolicy ") {
+		name := term[3]
+		ns.addNode("LoginSchemaPolicy", name, "", "", "")
+		if schema := termValue(term, "-action"); schema != "" {
+			ns.addNode("LoginSchema", schema, "", "", "")
+			ns.addEdge(name, schema, "", "LOGINSCHEMA")
+		}
+	} else if strings.HasPrefix(line, "add authentication negotiateAction ") {
+		// Kerberos tickets are validated against the KDCs of -domain
+		name := term[3]
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
+		ns.setNodeAttr(name, "domainUser", termValue(term, "-domainUser"))
+		if domain := termValue(term, "-domain"); domain != "" {
+			ns.addNode("Server", domain, "", "", "")
+			ns.addEdge(name, domain, "88", "KERBEROS")
+		}
+		ns.addURLEdge(name, termValue(term, "-NTLMPath"), "NTLM")
+	} else if strings.HasPrefix(line, "add authentication noAuthAction ") {
+		name := term[3]
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
 	} else if strings.HasPrefix(line, "add authentication OAuthAction ") {
 		name := term[3]
 		to := term[5]
@@ -56,11 +290,16 @@ func (ns *NSGraph) parseNSline(line string) {
 		to := term[7]
 		ns.addNode("AuthPolicy", name, "", "", "")
 		ns.addEdge(name, to, "", "")
-	} else if strings.HasPrefix(line, "add authentication policyLabel") {
+	} else if strings.HasPrefix(line, "add authentication policylabel ") {
+		// add authentication policylabel <name> [-type <type>] [-loginSchema <schema>]
 		name := term[3]
-		to := term[7]
-		ns.addNode("PolicyLabel", name, "", "", "LoginSchema")
-		ns.addEdge(name, to, "", "")
+		ns.addNode("PolicyLabel", name, "", "", "")
+		ns.setNodeAttr(name, "feature", "authentication")
+		ns.setNodeAttr(name, "type", termValue(term, "-type"))
+		if schema := termValue(term, "-loginSchema"); schema != "" {
+			ns.addNode("LoginSchema", schema, "", "", "")
+			ns.addEdge(name, schema, "", "LOGINSCHEMA")
+		}
 	} else if strings.HasPrefix(line, "add authentication radiusAction ") {
 		name := term[3]
 		to := term[5]
@@ -81,11 +320,41 @@ func (ns *NSGraph) parseNSline(line string) {
 		ns.addNode("Cert", cert, "", "", "CERT")
 		ns.addEdge(name, cert, "", "CERT")
 		ns.addEdge(name, to, "", "SAML")
+	} else if strings.HasPrefix(line, "add authentication samlIdPPolicy ") {
+		name := term[3]
+		ns.addNode("AuthPolicy", name, "", "", "")
+		if to := termValue(term, "-action"); to != "" {
+			ns.addNode("AuthAction", to, "", "", "")
+			ns.addEdge(name, to, "", "")
+		}
+	} else if strings.HasPrefix(line, "add authentication samlIdPProfile ") {
+		// the netscaler acting as SAML IdP for the service provider at -assertionConsumerServiceURL
+		name := term[3]
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
+		for _, opt := range []string{"-samlIdPCertName", "-samlSPCertName"} {
+			if cert := termValue(term, opt); cert != "" {
+				ns.addNode("Cert", cert, "", "", "CERT")
+				ns.addEdge(name, cert, "", "CERT")
+			}
+		}
+		ns.addURLEdge(name, termValue(term, "-assertionConsumerServiceURL"), "SAML")
 	} else if strings.HasPrefix(line, "add authentication samlPolicy ") {
 		name := term[3]
 		to := term[5]
 		ns.addNode("AuthPolicy", name, "", "", "")
 		ns.addEdge(name, to, "", "")
+	} else if strings.HasPrefix(line, "add authentication tacacsAction ") {
+		name := term[3]
+		port := termValue(term, "-serverPort")
+		if port == "" {
+			port = "49"
+		}
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
+		if ip := termValue(term, "-serverIP"); ip != "" {
+			ns.addEdge(name, ip, port, "TACACS")
+		}
 	} else if strings.HasPrefix(line, "add authentication vserver ") {
 		name := term[3]
 		protocol := term[4]
@@ -98,6 +367,66 @@ func (ns *NSGraph) parseNSline(line string) {
 			ns.addNode("VIP", "", vip, "", "")
 			ns.addEdge(vip, name, port, protocol)
 		}
+	} else if strings.HasPrefix(line, "add authentication webAuthAction ") {
+		name := term[3]
+		ns.addNode("AuthAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[2])
+		if ip := termValue(term, "-serverIP"); ip != "" {
+			ns.addEdge(name, ip, termValue(term, "-serverPort"), termValue(term, "-scheme"))
+		}
+	} else if strings.HasPrefix(line, "add authorization policy ") {
+		// add authorization policy <name> <rule> <ALLOW|DENY>
+		if len(term) < 6 {
+			return false
+		}
+		name := term[3]
+		ns.addNode("AuthorizationPolicy", name, "", "", "")
+		ns.setNodeAttr(name, "action", term[5])
+	} else if strings.HasPrefix(line, "add bot policy ") {
+		name := term[3]
+		profile := termValue(term, "-profileName")
+		ns.addNode("BotPolicy", name, "", "", "")
+		if profile != "" {
+			ns.addNode("BotProfile", profile, "", "", "")
+			ns.addEdge(name, profile, "", "")
+		}
+	} else if strings.HasPrefix(line, "add bot profile ") {
+		ns.addNode("BotProfile", term[3], "", "", "")
+	} else if strings.HasPrefix(line, "add cache contentGroup ") {
+		name := term[3]
+		ns.addNode("CacheContentGroup", name, "", "", "")
+		ns.setNodeAttr(name, "type", termValue(term, "-type"))
+		ns.setNodeAttr(name, "relExpiry", termValue(term, "-relExpiry"))
+	} else if strings.HasPrefix(line, "add cache policy ") {
+		// cache actions (CACHE, NOCACHE, INVAL...) are built in
+		name := term[3]
+		ns.addNode("CachePolicy", name, "", "", "")
+		ns.setNodeAttr(name, "action", termValue(term, "-action"))
+		if group := termValue(term, "-storeInGroup"); group != "" {
+			ns.addNode("CacheContentGroup", group, "", "", "")
+			ns.addEdge(name, group, "", "STORE")
+		}
+		idx := slices.Index(term, "-invalGroups")
+		for i := idx + 1; idx != -1 && i < len(term) && !strings.HasPrefix(term[i], "-"); i++ {
+			ns.addNode("CacheContentGroup", term[i], "", "", "")
+			ns.addEdge(name, term[i], "", "INVALIDATE")
+		}
+	} else if strings.HasPrefix(line, "add cmp action ") {
+		// add cmp action <name> <cmpType>
+		if len(term) < 5 {
+			return false
+		}
+		name := term[3]
+		ns.addNode("CMPAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[4])
+	} else if strings.HasPrefix(line, "add cmp policy ") {
+		// built in actions are COMPRESS, GZIP, DEFLATE and NOCOMPRESS
+		name := term[3]
+		ns.addNode("CMPPolicy", name, "", "", "")
+		if action := termValue(term, "-resAction"); action != "" {
+			ns.addNode("CMPAction", action, "", "", "")
+			ns.addEdge(name, action, "", "")
+		}
 	} else if strings.HasPrefix(line, "add cs action ") {
 		name := term[3]
 		ns.addNode("CSAction", name, "", "", "")
@@ -119,22 +448,22 @@ func (ns *NSGraph) parseNSline(line string) {
 			ns.addNode("CSAction", to, "", "", "")
 			ns.addEdge(name, to, "", "")
 		}
-		ruleIdx := slices.Index(term[:], "-rule")
-		if ruleIdx != -1 {
-			pipeRegex := regexp.MustCompile(`\|`)
-			hostRegex := regexp.MustCompile(`HTTP\.REQ\.HOSTNAME\.EQ\(\\"([^"]+)\\"\)`)
-			rules := pipeRegex.Split(term[ruleIdx+1], -1)
-			for _, rule := range rules {
-				if strings.Contains(rule, "HTTP.REQ.HOSTNAME.EQ") {
-					match := hostRegex.FindStringSubmatch(rule)
-					if len(match) > 1 {
-						domain := match[1]
-						ns.addNode("DomainName", domain, "", "", "")
-						ns.addEdge(domain, name, "", "")
-					}
-				}
-			}
+		matches := classicRuleMatches(term)
+		if rule := termValue(term, "-rule"); rule != "" {
+			matches = append(matches, parseRuleExpression(rule)...)
+		}
+		for _, m := range matches {
+			ns.addNode(m.nstype, m.value, "", "", "")
+			ns.addEdge(m.value, name, "", m.condition)
+		}
+	} else if strings.HasPrefix(line, "add cs policylabel ") {
+		if len(term) < 5 {
+			return false
 		}
+		name := term[3]
+		ns.addNode("PolicyLabel", name, "", "", "")
+		ns.setNodeAttr(name, "feature", "cs")
+		ns.setNodeAttr(name, "type", term[4])
 	} else if strings.HasPrefix(line, "add cs vserver ") {
 		name := term[3]
 		protocol := term[4]
@@ -147,23 +476,126 @@ func (ns *NSGraph) parseNSline(line string) {
 			ns.addNode("VIP", "", vip, "", "")
 			ns.addEdge(vip, name, port, protocol)
 		}
+		ns.addAuthnProfileEdge(name, term)
+	} else if strings.HasPrefix(line, "add dns addRec ") || strings.HasPrefix(line, "add dns aaaaRec ") {
+		// add dns addRec <hostName> <IPAddress> [-TTL <secs>]
+		if len(term) < 5 {
+			return false
+		}
+		recordType := "A"
+		if term[2] == "aaaaRec" {
+			recordType = "AAAA"
+		}
+		ns.addDNSRecord(term[3], term[4], recordType)
+		ns.setNodeAttr(term[3], "TTL", termValue(term, "-TTL"))
+	} else if strings.HasPrefix(line, "add dns cnameRec ") {
+		// add dns cnameRec <aliasName> <canonicalName> [-TTL <secs>]
+		if len(term) < 5 {
+			return false
+		}
+		ns.addDNSRecord(term[3], term[4], "CNAME")
+		ns.setNodeAttr(term[3], "TTL", termValue(term, "-TTL"))
+	} else if strings.HasPrefix(line, "add dns nameServer ") {
+		// add dns nameServer <IP>|<dnsVserverName> [-local] [-type UDP|TCP|UDP_TCP]
+		target := term[3]
+		protocol := termValue(term, "-type")
+		if protocol == "" {
+			protocol = "DNS"
+		}
+		if isIPAddress(target) {
+			ns.addNode("NameServer", target, "", "", "")
+			ns.addEdge("Global", target, "53", protocol)
+		} else {
+			// a DNS vserver resolves on the appliance's behalf
+			ns.addEdge("Global", target, "", "NAMESERVER")
+		}
+		if slices.Contains(term, "-local") {
+			ns.setNodeAttr(target, "local", "YES")
+		}
+	} else if strings.HasPrefix(line, "add dns nsRec ") {
+		// add dns nsRec <domain> <nameServer>, linked once the zone is known
+		if len(term) < 5 {
+			return false
+		}
+		ns.addDNSNameServer(term[3], term[4], "NS")
+	} else if strings.HasPrefix(line, "add dns soaRec ") {
+		// add dns soaRec <domain> -originServer <server> -contact <contact>
+		server := termValue(term, "-originServer")
+		if server == "" {
+			return false
+		}
+		ns.addDNSNameServer(term[3], server, "SOA")
+	} else if strings.HasPrefix(line, "add dns view ") {
+		ns.addNode("DNSView", term[3], "", "", "")
+	} else if strings.HasPrefix(line, "add dns zone ") {
+		// add dns zone <zoneName> -proxyMode YES|NO [-dnssecOffload ENABLED]
+		name := term[3]
+		ns.addNode("DNSZone", name, "", "", "")
+		for _, opt := range []string{"-proxyMode", "-dnssecOffload", "-nsec", "-type"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), termValue(term, opt))
+		}
+	} else if strings.HasPrefix(line, "add feo action ") {
+		ns.addNode("FEOAction", term[3], "", "", "")
+	} else if strings.HasPrefix(line, "add feo policy ") {
+		// add feo policy <name> <rule> <action>
+		if len(term) < 6 {
+			return false
+		}
+		name := term[3]
+		action := term[5]
+		ns.addNode("FEOPolicy", name, "", "", "")
+		ns.addNode("FEOAction", action, "", "", "")
+		ns.addEdge(name, action, "", "")
 	} else if strings.HasPrefix(line, "add gslb service ") {
 		name := term[3]
 		to := term[4] // ip
 		protocol := term[5]
 		port := term[6]
-		publicip := term[8]
+		publicip := termValue(term, "-publicIP")
 		ns.addNode("GSLBService", name, "", port, protocol)
 		ns.addEdge(name, to, port, protocol)
-		if to != publicip {
-			public_port := term[10]
+		if publicip != "" && to != publicip {
+			public_port := termValue(term, "-publicPort")
 			ns.addEdge(publicip, name, public_port, "")
 		}
+		ns.setNodeCluster(name, termValue(term, "-siteName"))
+	} else if strings.HasPrefix(line, "add gslb serviceGroup ") {
+		// add gslb serviceGroup <name> <protocol> [-siteName <site>]
+		if len(term) < 5 {
+			return false
+		}
+		name := term[3]
+		protocol := term[4]
+		ns.addNode("GSLBGroup", name, "", "", protocol)
+		ns.setNodeCluster(name, termValue(term, "-siteName"))
+	} else if strings.HasPrefix(line, "add gslb site ") {
+		// add gslb site <name> [-siteType <type>] <siteIPAddress> [-publicIP <ip>]
+		name := term[3]
+		ip := ""
+		for i := 4; i < len(term); i++ {
+			if strings.HasPrefix(term[i], "-") {
+				i++
+				continue
+			}
+			ip = term[i]
+			break
+		}
+		if ip == "" {
+			return false
+		}
+		ns.addNode("Netscaler", name, ip, "", "")
+		ns.setNodeCluster(ip, name)
+		for _, opt := range []string{"-siteType", "-publicIP", "-parentSite", "-triggerMonitor", "-metricExchange"} {
+			ns.setNodeAttr(ip, strings.TrimPrefix(opt, "-"), termValue(term, opt))
+		}
 	} else if strings.HasPrefix(line, "add gslb vserver ") {
 		name := term[3]
 		protocol := term[4]
 
 		ns.addNode("GSLBVServer", name, "", "", protocol)
+		for _, opt := range []string{"-lbMethod", "-backupLBMethod", "-persistenceType", "-persistenceId", "-timeout", "-tolerance"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), termValue(term, opt))
+		}
 		idx := slices.Index(term[:], "-backupVServer")
 		if idx != -1 {
 			backup := term[idx+1]
@@ -175,9 +607,55 @@ func (ns *NSGraph) parseNSline(line string) {
 	} else if strings.HasPrefix(line, "add ha node ") {
 		ip := term[4]
 		ns.addNode("Netscaler", "", ip, "", "")
+	} else if strings.HasPrefix(line, "add ica accessprofile ") || strings.HasPrefix(line, "add ica latencyprofile ") {
+		// HDX channel redirection or latency monitoring settings, e.g.
+		// -ClientClipboardRedirection DISABLED or -l7LatencyMonitoring ENABLED
+		name := term[3]
+		nstype := "ICAAccessProfile"
+		if term[2] == "latencyprofile" {
+			nstype = "ICALatencyProfile"
+		}
+		ns.addNode(nstype, name, "", "", "")
+		for i := 4; i+1 < len(term); i += 2 {
+			ns.setNodeAttr(name, strings.TrimPrefix(term[i], "-"), term[i+1])
+		}
+	} else if strings.HasPrefix(line, "add ica action ") {
+		// add ica action <name> [-accessProfileName <profile>] [-latencyProfileName <profile>]
+		name := term[3]
+		ns.addNode("ICAAction", name, "", "", "")
+		if profile := termValue(term, "-accessProfileName"); profile != "" {
+			ns.addNode("ICAAccessProfile", profile, "", "", "")
+			ns.addEdge(name, profile, "", "")
+		}
+		if profile := termValue(term, "-latencyProfileName"); profile != "" {
+			ns.addNode("ICALatencyProfile", profile, "", "", "")
+			ns.addEdge(name, profile, "", "")
+		}
+	} else if strings.HasPrefix(line, "add ica policy ") {
+		// add ica policy <name> -rule <rule> -action <action>
+		name := term[3]
+		ns.addNode("ICAPolicy", name, "", "", "")
+		if action := termValue(term, "-action"); action != "" {
+			ns.addNode("ICAAction", action, "", "", "")
+			ns.addEdge(name, action, "", "")
+		}
 	} else if strings.HasPrefix(line, "add lb group ") {
 		name := term[3]
 		ns.addNode("LBGroup", name, "", "", "")
+	} else if strings.HasPrefix(line, "add lb monitor ") {
+		// add lb monitor <name> <type> [-interval <secs>] ...
+		if len(term) < 5 {
+			return false
+		}
+		name := term[3]
+		ns.addNode("Monitor", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[4])
+		ns.setNodeAttr(name, "interval", termValue(term, "-interval"))
+		ns.setNodeAttr(name, "destIP", termValue(term, "-destIP"))
+		ns.setNodeAttr(name, "destPort", termValue(term, "-destPort"))
+		ns.setNodeAttr(name, "send", termValue(term, "-send"))
+		ns.setNodeAttr(name, "recv", termValue(term, "-recv"))
+		ns.setNodeAttr(name, "secure", termValue(term, "-secure"))
 	} else if strings.HasPrefix(line, "add lb vserver ") {
 		// fmt.Printf("line: %s\n", line)
 		// fmt.Println(term)
@@ -192,6 +670,7 @@ func (ns *NSGraph) parseNSline(line string) {
 			ns.addNode("VIP", "", vip, "", "")
 			ns.addEdge(vip, name, port, protocol)
 		}
+		ns.addAuthnProfileEdge(name, term)
 		backupIdx := slices.Index(term[:], "-backupVServer")
 		if backupIdx != -1 {
 			backup := term[backupIdx+1]
@@ -201,21 +680,119 @@ func (ns *NSGraph) parseNSline(line string) {
 	} else if strings.HasPrefix(line, "add ns ip ") {
 		ip := term[3]
 		ns.addNode("Netscaler", "", ip, "", "")
+	} else if strings.HasPrefix(line, "add policy dataset ") {
+		if len(term) < 5 {
+			return false
+		}
+		name := term[3]
+		ns.addNode("DataSet", name, "", "", "")
+		ns.setNodeAttr(name, "type", term[4])
+	} else if strings.HasPrefix(line, "add policy expression ") {
+		if len(term) < 5 {
+			return false
+		}
+		name := term[3]
+		ns.addNode("PolicyExpression", name, "", "", "")
+		ns.setNodeAttr(name, "expression", exprValue(term[4]))
+		ns.expressions[name] = true
+	} else if strings.HasPrefix(line, "add policy httpCallout ") {
+		name := term[3]
+		ns.addNode("HTTPCallout", name, "", "", "")
+		for _, opt := range []string{"-returnType", "-httpMethod", "-hostExpr", "-urlStemExpr", "-resultExpr", "-scheme"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), exprValue(termValue(term, opt)))
+		}
+		if vserver := termValue(term, "-vServer"); vserver != "" {
+			ns.addEdge(name, vserver, "", termValue(term, "-scheme"))
+		} else if ip := termValue(term, "-IPAddress"); ip != "" {
+			ns.addEdge(name, ip, termValue(term, "-port"), termValue(term, "-scheme"))
+		}
+	} else if strings.HasPrefix(line, "add policy patset ") {
+		ns.addNode("PatSet", term[3], "", "", "")
+	} else if strings.HasPrefix(line, "add policy stringmap ") {
+		ns.addNode("StringMap", term[3], "", "", "")
 	} else if strings.HasPrefix(line, "add responder action ") {
+		if len(term) < 5 {
+			return false
+		}
 		name := term[3]
+		actionType := strings.ToLower(term[4])
 		ns.addNode("ResponderAction", name, "", "", "")
+		ns.setNodeAttr(name, "type", actionType)
+		ns.setNodeAttr(name, "responseStatusCode", termValue(term, "-responseStatusCode"))
+		target := ""
+		if len(term) > 5 && !strings.HasPrefix(term[5], "-") {
+			target = term[5]
+		}
+		switch actionType {
+		case "noop", "sqlresponse_ok", "sqlresponse_error":
+			// these actions take no target
+		case "redirect":
+			// only a fixed URL is drawn, dynamic redirects are kept as text
+			if url, ok := stringLiteral(target); ok {
+				ns.addNode("URL", url, "", "", "")
+				ns.addEdge(name, url, "", "REDIRECT")
+			} else {
+				ns.setNodeAttr(name, "target", exprValue(target))
+			}
+		case "respondwithhtmlpage":
+			page := termValue(term, "-htmlpage")
+			if page == "" {
+				page = target
+			}
+			if page != "" {
+				ns.addNode("HTMLPage", page, "", "", "")
+				ns.addEdge(name, page, "", "HTMLPAGE")
+			}
+		default:
+			ns.setNodeAttr(name, "target", exprValue(target))
+		}
+		ns.addExpressionReferences(name, target)
+	} else if strings.HasPrefix(line, "add responder policylabel ") {
+		name := term[3]
+		ns.addNode("PolicyLabel", name, "", "", "")
+		ns.setNodeAttr(name, "feature", "responder")
+		ns.setNodeAttr(name, "type", termValue(term, "-policylabeltype"))
 	} else if strings.HasPrefix(line, "add responder policy ") {
 		name := term[3]
 		to := term[5]
 		ns.addNode("ResponderPolicy", name, "", "", "")
 		ns.addEdge(name, to, "", "")
 	} else if strings.HasPrefix(line, "add rewrite action ") {
+		// add rewrite action <name> <type> [<target>] [<stringBuilderExpr>] [-search <expr>]
+		if len(term) < 5 {
+			return false
+		}
 		name := term[3]
 		ns.addNode("RewriteAction", name, "", "", "")
-		if term[4] != "replace" {
-			to := term[5]
-			ns.addEdge(name, to, "", "")
+		ns.setNodeAttr(name, "type", strings.ToLower(term[4]))
+		target, expression := "", ""
+		if len(term) > 5 && !strings.HasPrefix(term[5], "-") {
+			target = term[5]
+			ns.setNodeAttr(name, "target", exprValue(target))
+		}
+		if len(term) > 6 && !strings.HasPrefix(term[6], "-") {
+			expression = term[6]
+			ns.setNodeAttr(name, "expression", exprValue(expression))
+		}
+		search := exprValue(termValue(term, "-search"))
+		ns.setNodeAttr(name, "search", search)
+		if m := searchRegex.FindStringSubmatch(search); m != nil {
+			nstype := "PatSet"
+			if strings.EqualFold(m[1], "dataset") {
+				nstype = "DataSet"
+			}
+			ns.addNode(nstype, m[2], "", "", "")
+			ns.addEdge(name, m[2], "", "SEARCH")
+		}
+		ns.addExpressionReferences(name, target, expression)
+	} else if strings.HasPrefix(line, "add rewrite policylabel ") {
+		if len(term) < 5 {
+			return false
 		}
+		name := term[3]
+		ns.addNode("PolicyLabel", name, "", "", "")
+		ns.setNodeAttr(name, "feature", "rewrite")
+		ns.setNodeAttr(name, "type", term[4])
 	} else if strings.HasPrefix(line, "add rewrite policy ") {
 		name := term[3]
 		to := term[5]
@@ -230,6 +807,13 @@ func (ns *NSGraph) parseNSline(line string) {
 		to := term[3]
 		port := term[5]
 		protocol := term[4]
+		if strings.HasPrefix(protocol, "ADNS") {
+			// the appliance answers DNS queries on this address itself
+			ns.addNode("Service", name, "", port, protocol)
+			ns.addNode("VIP", "", to, "", "")
+			ns.addEdge(to, name, port, protocol)
+			return true
+		}
 		ns.addNode("Service", name, "", "", "")
 		ns.addEdge(name, to, port, protocol)
 	} else if strings.HasPrefix(line, "add serviceGroup ") {
@@ -239,7 +823,148 @@ func (ns *NSGraph) parseNSline(line string) {
 	} else if strings.HasPrefix(line, "add ssl certkey ") {
 		name := term[3]
 		ns.addNode("Cert", name, "", "", "CERT")
+		ns.setNodeAttr(name, "certFile", termValue(term, "-cert"))
+		ns.setNodeAttr(name, "keyFile", termValue(term, "-key"))
+	} else if strings.HasPrefix(line, "add ssl cipher ") {
+		name := term[3]
+		ns.addNode("CipherGroup", name, "", "", "")
+	} else if strings.HasPrefix(line, "add ssl profile ") {
+		name := term[3]
+		ns.addNode("SSLProfile", name, "", "", "")
+		for _, option := range []string{"-sslProfileType", "-ssl3", "-tls1", "-tls11", "-tls12", "-tls13", "-SNIEnable", "-HSTS"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(option, "-"), termValue(term, option))
+		}
 
+	} else if strings.HasPrefix(line, "add tm sessionAction ") {
+		name := term[3]
+		ns.addNode("SessionAction", name, "", "", "")
+		ns.setNodeAttr(name, "feature", "tm")
+		for _, opt := range []string{"-SSO", "-ssoCredential", "-ssoDomain", "-defaultAuthorizationAction"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), termValue(term, opt))
+		}
+	} else if strings.HasPrefix(line, "add tm sessionPolicy ") {
+		// add tm sessionPolicy <name> <rule> <action>
+		if len(term) < 6 {
+			return false
+		}
+		name := term[3]
+		to := term[5]
+		ns.addNode("SessionPolicy", name, "", "", "")
+		ns.setNodeAttr(name, "feature", "tm")
+		ns.addNode("SessionAction", to, "", "", "")
+		ns.addEdge(name, to, "", "")
+	} else if strings.HasPrefix(line, "add tm trafficAction ") || strings.HasPrefix(line, "add vpn trafficAction ") {
+		// single sign-on settings, e.g. -SSO ON -kcdAccount <account>
+		name := term[3]
+		ns.addNode("TrafficAction", name, "", "", "")
+		ns.setNodeAttr(name, "feature", term[1])
+		if term[1] == "vpn" && len(term) > 4 && !strings.HasPrefix(term[4], "-") {
+			// add vpn trafficAction <name> <qual>
+			ns.setNodeAttr(name, "qual", term[4])
+		}
+		for _, opt := range []string{"-SSO", "-formSSOAction", "-samlSSOProfile", "-kcdAccount", "-userExpression", "-passwdExpression"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), exprValue(termValue(term, opt)))
+		}
+	} else if strings.HasPrefix(line, "add tm trafficPolicy ") || strings.HasPrefix(line, "add vpn trafficPolicy ") {
+		// add tm trafficPolicy <name> <rule> <action>
+		if len(term) < 6 {
+			return false
+		}
+		name := term[3]
+		to := term[5]
+		ns.addNode("TrafficPolicy", name, "", "", "")
+		ns.setNodeAttr(name, "feature", term[1])
+		ns.addNode("TrafficAction", to, "", "", "")
+		ns.addEdge(name, to, "", "")
+	} else if strings.HasPrefix(line, "add transform action ") {
+		// add transform action <name> <profileName> [<priority>]
+		if len(term) < 5 {
+			return false
+		}
+		name := term[3]
+		profile := term[4]
+		priority := ""
+		if len(term) > 5 && !strings.HasPrefix(term[5], "-") {
+			priority = term[5]
+		}
+		ns.addNode("TransformAction", name, "", "", "")
+		ns.addNode("TransformProfile", profile, "", "", "")
+		ns.addEdgeAttrs(profile, name, "", "", map[string]string{"priority": priority})
+	} else if strings.HasPrefix(line, "add transform policy ") {
+		// add transform policy <name> <rule> <profileName>
+		if len(term) < 6 {
+			return false
+		}
+		name := term[3]
+		profile := term[5]
+		ns.addNode("TransformPolicy", name, "", "", "")
+		ns.addNode("TransformProfile", profile, "", "", "")
+		ns.addEdge(name, profile, "", "")
+	} else if strings.HasPrefix(line, "add transform profile ") {
+		name := term[3]
+		ns.addNode("TransformProfile", name, "", "", "")
+		ns.setNodeAttr(name, "type", termValue(term, "-type"))
+	} else if strings.HasPrefix(line, "add vpn clientlessAccessPolicy ") {
+		// add vpn clientlessAccessPolicy <name> <rule> <profileName>
+		if len(term) < 6 {
+			return false
+		}
+		name := term[3]
+		profile := term[5]
+		ns.addNode("ClientlessAccessPolicy", name, "", "", "")
+		ns.addNode("ClientlessAccessProfile", profile, "", "", "")
+		ns.addEdge(name, profile, "", "")
+	} else if strings.HasPrefix(line, "add vpn clientlessAccessProfile ") {
+		// rewrite policy labels applied to proxied content, e.g.
+		// -URLRewritePolicyLabel <label> -ReqHdrRewritePolicyLabel <label>
+		name := term[3]
+		ns.addNode("ClientlessAccessProfile", name, "", "", "")
+		for i := 4; i+1 < len(term); i += 2 {
+			opt := strings.TrimPrefix(term[i], "-")
+			if strings.HasSuffix(opt, "RewritePolicyLabel") {
+				ns.addNode("PolicyLabel", term[i+1], "", "", "")
+				ns.addEdge(name, term[i+1], "", strings.TrimSuffix(opt, "PolicyLabel"))
+				continue
+			}
+			ns.setNodeAttr(name, opt, exprValue(term[i+1]))
+		}
+	} else if strings.HasPrefix(line, "add vpn intranetApplication ") {
+		// add vpn intranetApplication <name> <protocol> <destIP> [-netmask <mask>] [-destPort <ports>]
+		// add vpn intranetApplication <name> <protocol> -hostName <host> | -iprange <from-to>
+		if len(term) < 5 {
+			return false
+		}
+		name := term[3]
+		protocol := term[4]
+		dest := ""
+		if len(term) > 5 && !strings.HasPrefix(term[5], "-") {
+			dest = term[5]
+		}
+		host := termValue(term, "-hostName")
+		ipRange := termValue(term, "-iprange")
+		ns.addNode("IntranetApp", name, "", "", "")
+		for _, opt := range []string{"-netmask", "-iprange", "-hostName", "-interception", "-spoofIP", "-srcIP", "-srcPort"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), termValue(term, opt))
+		}
+		switch {
+		case ipRange != "" || (dest != "" && termValue(term, "-netmask") != "") || strings.Contains(host, "*"):
+			// a network or wildcard domain rather than a single server
+			destination := dest
+			if ipRange != "" {
+				destination = ipRange
+			} else if host != "" {
+				destination = host
+			}
+			ns.setNodeAttr(name, "destination", destination)
+			ns.setNodeAttr(name, "destPort", termValue(term, "-destPort"))
+			ns.setNodeAttr(name, "protocol", protocol)
+		case host != "":
+			ns.addNode("Server", host, "", "", "")
+			ns.addEdge(name, host, termValue(term, "-destPort"), protocol)
+		case dest != "":
+			ns.addNode("Server", dest, "", "", "")
+			ns.addEdge(name, dest, termValue(term, "-destPort"), protocol)
+		}
 	} else if strings.HasPrefix(line, "add vpn portaltheme ") {
 		name := term[3]
 		ns.addNode("PortalTheme", name, "", "", "")
@@ -251,18 +976,24 @@ func (ns *NSGraph) parseNSline(line string) {
 	} else if strings.HasPrefix(line, "add vpn sessionAction ") {
 		name := term[3]
 		ns.addNode("SessionAction", name, "", "", "")
-		// if strings.Contains(line, "-wihome") {
-		idx := slices.Index(term[:], "-wihome")
-		if idx != -1 {
-			wi := term[idx+1]
-			ns.addNode("WI", wi, "", "", "")
-			ns.addEdge(name, wi, "", "wi")
-		}
+		ns.addSessionSettings(name, term)
 	} else if strings.HasPrefix(line, "add vpn sessionPolicy ") {
 		name := term[3]
 		to := term[5]
 		ns.addNode("SessionPolicy", name, "", "", "")
 		ns.addEdge(name, to, "", "")
+	} else if strings.HasPrefix(line, "add vpn url ") {
+		// add vpn url <name> <linkName> <actualURL> [-clientlessAccess ON]
+		if len(term) < 6 {
+			return false
+		}
+		name := term[3]
+		ns.addNode("VPNURL", name, "", "", "")
+		ns.setNodeAttr(name, "linkName", term[4])
+		for _, opt := range []string{"-clientlessAccess", "-applicationType", "-vServerName", "-SAMLSSOProfile"} {
+			ns.setNodeAttr(name, strings.TrimPrefix(opt, "-"), termValue(term, opt))
+		}
+		ns.addURLEdge(name, term[5], "")
 	} else if strings.HasPrefix(line, "add vpn vserver ") {
 		name := term[3]
 		protocol := term[4]
@@ -275,10 +1006,49 @@ func (ns *NSGraph) parseNSline(line string) {
 			ns.addNode("VIP", "", vip, "", "")
 			ns.addEdge(vip, name, port, protocol)
 		}
+		ns.setNodeAttr(name, "icaOnly", termValue(term, "-icaOnly"))
+		ns.addAuthnProfileEdge(name, term)
+	} else if strings.HasPrefix(line, "import responder htmlpage ") {
+		// import responder htmlpage <src> <name>
+		if len(term) < 5 {
+			return false
+		}
+		name := term[4]
+		ns.addNode("HTMLPage", name, "", "", "")
+		ns.setNodeAttr(name, "source", term[3])
+	} else if strings.HasPrefix(line, "bind aaa group ") || strings.HasPrefix(line, "bind aaa user ") {
+		name := term[3]
+		user := termValue(term, "-userName")
+		policy := termValue(term, "-policy")
+		resources := ns.addVPNResourceEdges(name, term)
+		if user == "" && policy == "" && !resources {
+			return false
+		}
+		if user != "" {
+			ns.addNode("AAAUser", user, "", "", "")
+			ns.addEdge(name, user, "", "MEMBER")
+		}
+		if policy != "" {
+			ns.addEdgeAttrs(name, policy, "", "", bindAttrs(term))
+		}
+	} else if strings.HasPrefix(line, "bind appfw profile ") || strings.HasPrefix(line, "bind bot profile ") {
+		// count the relaxations and rules bound by type, e.g. -startURL
+		if len(term) < 5 || !strings.HasPrefix(term[4], "-") {
+			return false
+		}
+		ns.countNodeAttr(term[3], strings.TrimPrefix(term[4], "-"))
 	} else if strings.HasPrefix(line, "bind authentication policylabel ") {
+		// bind authentication policylabel <label> -policyName <policy> -priority <priority> [-nextFactor <label>]
 		name := term[3]
-		to := term[5]
-		ns.addEdge(name, to, "", "")
+		policy := termValue(term, "-policyName")
+		if policy == "" {
+			return false
+		}
+		ns.addEdgeAttrs(name, policy, "", "", bindAttrs(term))
+		if next := termValue(term, "-nextFactor"); next != "" {
+			ns.addNode("PolicyLabel", next, "", "", "")
+			ns.addEdge(policy, next, "", "nFactor")
+		}
 	} else if strings.HasPrefix(line, "bind authentication vserver ") {
 		name := term[3]
 		idx := slices.Index(term[:], "-policy")
@@ -287,19 +1057,21 @@ func (ns *NSGraph) parseNSline(line string) {
 			if !strings.HasPrefix(policy, "_") {
 				nfIdx := slices.Index(term[:], "-nextFactor")
 				if nfIdx != -1 {
-					ns.addEdge(name, policy, "", "nFactor")
+					ns.addEdgeAttrs(name, policy, "", "nFactor", bindAttrs(term))
 					next := term[nfIdx+1]
+					ns.addNode("PolicyLabel", next, "", "", "")
 					ns.addEdge(policy, next, "", "nFactor")
 				} else {
-					ns.addEdge(name, policy, "", "")
+					ns.addEdgeAttrs(name, policy, "", "", bindAttrs(term))
 				}
+				ns.addInvokeEdge(name, policy, term)
 			}
 		}
 
 		idx = slices.Index(term[:], "-policyLabel")
 		if idx != -1 {
 			label := term[idx+1]
-			ns.addEdge(name, label, "", "")
+			ns.addEdgeAttrs(name, label, "", "", bindAttrs(term))
 		}
 
 		idx = slices.Index(term[:], "-portaltheme")
@@ -308,6 +1080,19 @@ func (ns *NSGraph) parseNSline(line string) {
 			ns.addEdge(name, theme, "", "")
 		}
 
+	} else if strings.HasPrefix(line, "bind cs policylabel ") || strings.HasPrefix(line, "bind responder policylabel ") || strings.HasPrefix(line, "bind rewrite policylabel ") {
+		if len(term) < 5 {
+			return false
+		}
+		name := term[3]
+		policy := term[4]
+		ns.addNode("PolicyLabel", name, "", "", "")
+		ns.addEdgeAttrs(name, policy, "", "", positionalBindAttrs(term, 5))
+		ns.addInvokeEdge(name, policy, term)
+		target := termValue(term, "-targetVserver")
+		if target != "" {
+			ns.addEdge(policy, target, "", "")
+		}
 	} else if strings.HasPrefix(line, "bind cs vserver") {
 		name := term[3]
 		policy := term[5]
@@ -315,7 +1100,8 @@ func (ns *NSGraph) parseNSline(line string) {
 		polIdx := slices.Index(term[:], "-policyName")
 		if polIdx != -1 {
 			policy := term[polIdx+1]
-			ns.addEdge(name, policy, "", "")
+			ns.addEdgeAttrs(name, policy, "", "", bindAttrs(term))
+			ns.addInvokeEdge(name, policy, term)
 		}
 
 		lbIdx := slices.Index(term[:], "-targetLBVserver")
@@ -325,13 +1111,22 @@ func (ns *NSGraph) parseNSline(line string) {
 			ns.addNode("CSPolicy", policy, "", "", "")     // TODO
 			ns.addEdge(policy, lbvserver, "", "")
 		}
-	} else if strings.HasPrefix(line, "bind gslb service ") {
-		idx := slices.Index(term[:], "-viewName")
-		if idx != -1 {
-			name := term[3]
-			protocol := term[idx+1]
-			target := term[idx+2]
-			ns.addEdge(name, target, "", protocol)
+	} else if strings.HasPrefix(line, "bind gslb service ") || strings.HasPrefix(line, "bind gslb serviceGroup ") {
+		// bind gslb service <name> -viewName <view> <viewIP>, answering
+		// clients in the view with viewIP instead of the service address
+		name := term[3]
+		if idx := slices.Index(term, "-viewName"); idx != -1 && idx+2 < len(term) {
+			view := term[idx+1]
+			ns.addNode("DNSView", view, "", "", "")
+			ns.addEdgeAttrs(name, view, "", "VIEW", map[string]string{"viewIP": term[idx+2]})
+		} else if monitor := termValue(term, "-monitorName"); monitor != "" {
+			ns.addNode("Monitor", monitor, "", "", "")
+			ns.addEdge(name, monitor, "", "MONITOR")
+		} else if term[2] == "serviceGroup" && len(term) > 5 && !strings.HasPrefix(term[4], "-") {
+			// bind gslb serviceGroup <name> <server> <port>
+			ns.addEdge(name, term[4], term[5], "")
+		} else {
+			return false
 		}
 	} else if strings.HasPrefix(line, "bind gslb vserver ") {
 		name := term[3]
@@ -347,44 +1142,97 @@ func (ns *NSGraph) parseNSline(line string) {
 			service := term[serviceIdx+1]
 			ns.addEdge(name, service, "", "gslb")
 		}
+		if group := termValue(term, "-serviceGroupName"); group != "" {
+			ns.addNode("GSLBGroup", group, "", "", "")
+			ns.addEdge(name, group, "", "gslb")
+		}
 	} else if strings.HasPrefix(line, "bind ha ") {
 		// TODO:
+		return false
 	} else if strings.HasPrefix(line, "bind lb group ") {
 		name := term[3]
 		target := term[4]
 		ns.addEdge(name, target, "", "")
+	} else if strings.HasPrefix(line, "bind lb monitor ") {
+		if len(term) < 5 {
+			return false
+		}
+		monitor := term[3]
+		service := term[4]
+		ns.addNode("Monitor", monitor, "", "", "")
+		ns.addEdge(service, monitor, "", "MONITOR")
 	} else if strings.HasPrefix(line, "bind lb vserver ") {
 		name := term[3]
 		idx := slices.Index(term[:], "-policyName")
 		if idx != -1 {
 			policy := term[idx+1]
-			ns.addEdge(name, policy, "", "")
+			ns.addEdgeAttrs(name, policy, "", "", bindAttrs(term))
+			ns.addInvokeEdge(name, policy, term)
 		} else {
 			target := term[4] // server or serviceGroup
 			ns.addEdge(name, target, "", "")
 		}
 	} else if strings.HasPrefix(line, "bind ns ") {
 		// TODO:
+		return false
+	} else if strings.HasPrefix(line, "bind policy dataset ") || strings.HasPrefix(line, "bind policy patset ") {
+		// each line binds one entry, which may itself contain commas
+		if len(term) < 5 {
+			return false
+		}
+		ns.appendNodeAttr(term[3], "entries", term[4])
+		ns.patsetEntries[term[3]] = append(ns.patsetEntries[term[3]], term[4])
+	} else if strings.HasPrefix(line, "bind policy stringmap ") {
+		// bind policy stringmap <name> <key> <value>
+		if len(term) < 6 {
+			return false
+		}
+		ns.appendNodeAttr(term[3], "entries", term[4]+"="+term[5])
 	} else if strings.HasPrefix(line, "bind responder cs vserver ") {
 		name := term[3]
 		to := term[5]
-		ns.addEdge(name, to, "", "")
-	} else if strings.HasPrefix(line, "bind responder global ") {
-		name := term[3]
-		ns.addEdge("Global", name, "", "") // global to policy
-	} else if strings.HasPrefix(line, "bind responder ssl vserver ") {
-		if !strings.Contains(line, "eccCurveName") && !strings.Contains(line, "cipherName") {
-			name := term[3]
-			cert := term[5]
-			ns.addNode("Cert", cert, "", "", "CERT")
-			ns.addEdge(name, cert, "", "CERT")
+		ns.addEdgeAttrs(name, to, "", "", bindAttrs(term))
+	} else if globalBindRegex.MatchString(line) {
+		// global bind points, e.g. bind rewrite global <policy> <priority>
+		// or bind audit syslogGlobal -policyName <policy> -priority <priority>
+		feature := term[1]
+		policy := termValue(term, "-policyName")
+		attrs := bindAttrs(term)
+		if policy == "" && len(term) > 3 && !strings.HasPrefix(term[3], "-") {
+			policy = term[3]
+			attrs = positionalBindAttrs(term, 4)
+		}
+		handled := ns.addVPNResourceEdges("Global", term)
+		if sta := termValue(term, "-staServer"); sta != "" {
+			ns.addNode("STA", sta, "", "", "")
+			ns.addEdge("Global", sta, "", "STA")
+			handled = true
+		}
+		if theme := termValue(term, "-portaltheme"); theme != "" {
+			ns.addNode("PortalTheme", theme, "", "", "")
+			ns.addEdge("Global", theme, "", "")
+			handled = true
+		}
+		if policy == "" {
+			if !handled && len(term) > 4 && strings.HasPrefix(term[3], "-") {
+				// other globally bound objects, e.g. bind vpn global -sslCertkey <cert>
+				ns.addEdge("Global", term[4], "", "")
+				handled = true
+			}
+			return handled
 		}
+		attrs["feature"] = feature
+		ns.addNode("Policy", policy, "", "", "")
+		ns.addEdgeAttrs("Global", policy, "", attrs["type"], attrs) // global to policy
+		ns.addInvokeEdge("Global", policy, term)
 	} else if strings.HasPrefix(line, "bind responder vpn ") {
 		// TODO:
+		return false
 	} else if strings.HasPrefix(line, "bind server ") {
 		// TODO:
 		// name := term[3]
 		// ns.add_edge(name, cert, "", "CERT")
+		return false
 
 	} else if strings.HasPrefix(line, "bind service ") {
 		name := term[2]
@@ -392,6 +1240,10 @@ func (ns *NSGraph) parseNSline(line string) {
 		if idx == -1 {
 			target := term[3]
 			ns.addEdge(name, target, "", "")
+		} else {
+			monitor := term[idx+1]
+			ns.addNode("Monitor", monitor, "", "", "")
+			ns.addEdge(name, monitor, "", "MONITOR")
 		}
 	} else if strings.HasPrefix(line, "bind serviceGroup ") {
 		name := term[2]
@@ -400,15 +1252,51 @@ func (ns *NSGraph) parseNSline(line string) {
 			target := term[3]
 			port := term[4]
 			ns.addEdge(name, target, port, "")
+		} else {
+			monitor := term[idx+1]
+			ns.addNode("Monitor", monitor, "", "", "")
+			ns.addEdge(name, monitor, "", "MONITOR")
 		}
-	} else if strings.HasPrefix(line, "bind ssl vserver ") {
+	} else if strings.HasPrefix(line, "bind ssl cipher ") {
 		name := term[3]
-		cert := term[5]
-		ns.addNode("Cert", cert, "", "", "CERT")
-		ns.addEdge(name, cert, "", "CERT")
-	} else if strings.HasPrefix(line, "bind vpn global ") {
-		name := term[4]
-		ns.addEdge("0.0.0.0", name, "", "")
+		cipher := termValue(term, "-cipherName")
+		ns.addNode("CipherGroup", name, "", "", "")
+		ns.appendNodeAttr(name, "ciphers", cipher)
+	} else if strings.HasPrefix(line, "bind ssl profile ") {
+		name := term[3]
+		cipher := termValue(term, "-cipherName")
+		if cipher != "" {
+			ns.addNode("CipherGroup", cipher, "", "", "")
+			ns.addEdge(name, cipher, "", "CIPHER")
+		}
+	} else if strings.HasPrefix(line, "bind ssl vserver ") || strings.HasPrefix(line, "bind ssl service ") || strings.HasPrefix(line, "bind ssl serviceGroup ") {
+		name := term[3]
+		cert := termValue(term, "-certkeyName")
+		cipher := termValue(term, "-cipherName")
+		if cert == "" && cipher == "" {
+			// e.g. -eccCurveName, which is not drawn
+			return false
+		}
+		if cert != "" {
+			switch {
+			case slices.Contains(term, "-CA"):
+				ns.addNode("CACert", cert, "", "", "CERT")
+				ns.addEdgeAttrs(name, cert, "", "CACERT", map[string]string{
+					"ocspCheck": termValue(term, "-ocspCheck"),
+					"crlCheck":  termValue(term, "-crlCheck"),
+				})
+			case slices.Contains(term, "-SNICert"):
+				ns.addNode("Cert", cert, "", "", "CERT")
+				ns.addEdge(name, cert, "", "SNICERT")
+			default:
+				ns.addNode("Cert", cert, "", "", "CERT")
+				ns.addEdge(name, cert, "", "CERT")
+			}
+		}
+		if cipher != "" {
+			ns.addNode("CipherGroup", cipher, "", "", "")
+			ns.addEdge(name, cipher, "", "CIPHER")
+		}
 	} else if strings.HasPrefix(line, "bind vpn vserver ") {
 		name := term[3]
 		staIdx := slices.Index(term[:], "-staServer")
@@ -422,7 +1310,8 @@ func (ns *NSGraph) parseNSline(line string) {
 		if polIdx != -1 {
 			policy := term[polIdx+1]
 			if !strings.HasPrefix(policy, "_") {
-				ns.addEdge(name, policy, "", "")
+				ns.addEdgeAttrs(name, policy, "", "", bindAttrs(term))
+				ns.addInvokeEdge(name, policy, term)
 			}
 		}
 		porIdx := slices.Index(term[:], "-portaltheme")
@@ -430,17 +1319,49 @@ func (ns *NSGraph) parseNSline(line string) {
 			portaltheme := term[porIdx+1]
 			ns.addEdge(name, portaltheme, "", "")
 		}
+		ns.addVPNResourceEdges(name, term)
+		if profile := termValue(term, "-icaProfile"); profile != "" {
+			ns.addNode("ICAAccessProfile", profile, "", "", "")
+			ns.addEdge(name, profile, "", "ICA")
+		}
 	} else if strings.HasPrefix(line, "link ssl certkey ") {
 		name := term[3]
 		cert := term[4]
-		ns.addNode("Cert", cert, "", "", "CERT")
-		ns.addEdge(name, cert, "", "CERT")
+		ns.addNode("CACert", cert, "", "", "CERT")
+		ns.addEdge(name, cert, "", "CHAIN")
+	} else if strings.HasPrefix(line, "set ssl vserver ") || strings.HasPrefix(line, "set ssl service ") || strings.HasPrefix(line, "set ssl serviceGroup ") {
+		name := term[3]
+		profile := termValue(term, "-sslProfile")
+		if profile == "" {
+			return false
+		}
+		ns.addNode("SSLProfile", profile, "", "", "")
+		ns.addEdge(name, profile, "", "SSLPROFILE")
+	} else if strings.HasPrefix(line, "set transform action ") {
+		// URL rewrite rules, e.g. -requrlFrom <pattern> -requrlInto <pattern>
+		name := term[3]
+		for i := 4; i+1 < len(term); i += 2 {
+			ns.setNodeAttr(name, strings.TrimPrefix(term[i], "-"), exprValue(term[i+1]))
+		}
+	} else if strings.HasPrefix(line, "set cs vserver ") || strings.HasPrefix(line, "set lb vserver ") || strings.HasPrefix(line, "set vpn vserver ") {
+		if termValue(term, "-authnProfile") == "" {
+			return false
+		}
+		ns.addAuthnProfileEdge(term[3], term)
+	} else if strings.HasPrefix(line, "set vpn parameter ") {
+		// global session settings apply to every VPN vserver, e.g. -icaProxy ON
+		return ns.addSessionSettings("Global", term)
 	} else if strings.HasPrefix(line, "set ns config ") {
 		if term[3] == "-IPAddress" {
 			ip := term[4]
 			ns.addNode("Netscaler", "", ip, "", "")
 		}
+	} else {
+		return false
 	}
 
-	// return nil
+	if strings.HasPrefix(line, "add ") && len(term) > 3 && isPolicyCommand(term[2]) {
+		ns.addExpressionReferences(term[3], ruleText(term))
+	}
+	return true
 }
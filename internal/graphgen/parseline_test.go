@@ -0,0 +1,728 @@
+package graphgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parseLines runs each config line through parseNSline, as Parse does, and
+// returns the graph before edges are relabelled.
+func parseLines(t *testing.T, lines ...string) *NSGraph {
+	t.Helper()
+	ns := New("LR", []string{}, []string{}, []string{})
+	ns.addNode("VIP", "Global", "0.0.0.0", "", "")
+	for _, line := range lines {
+		ns.lineNum++
+		ns.parseNSline(line)
+	}
+	return ns
+}
+
+func findNode(ns *NSGraph, name string) *nsNode {
+	for i, n := range ns.Nodes {
+		if n.name == name || n.ip == name {
+			return &ns.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func findEdge(ns *NSGraph, from, to string) *nsEdge {
+	for i, e := range ns.Edges {
+		if e.from == from && e.to == to {
+			return &ns.Edges[i]
+		}
+	}
+	return nil
+}
+
+type wantNode struct {
+	nstype string
+	name   string
+	attrs  map[string]string
+}
+
+type wantEdge struct {
+	from, to, protocol string
+	attrs              map[string]string
+}
+
+func TestParseNSline(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		parsed  bool // whether the last line is parsed
+		nodes   []wantNode
+		edges   []wantEdge
+		noNodes []string
+		noEdges [][2]string
+	}{
+		{
+			name:   "responder sqlresponse action without target",
+			lines:  []string{"add responder action act_sql sqlresponse_ok"},
+			parsed: true,
+			nodes:  []wantNode{{"ResponderAction", "act_sql", map[string]string{"type": "sqlresponse_ok"}}},
+		},
+		{
+			name:   "responder sqlresponse_error action without target",
+			lines:  []string{"add responder action act_sqlerr sqlresponse_error"},
+			parsed: true,
+			nodes:  []wantNode{{"ResponderAction", "act_sqlerr", map[string]string{"type": "sqlresponse_error"}}},
+		},
+		{
+			name:   "responder redirect to fixed url",
+			lines:  []string{`add responder action act_moved redirect "\"https://www.example.com/\"" -responseStatusCode 301`},
+			parsed: true,
+			nodes: []wantNode{
+				{"ResponderAction", "act_moved", map[string]string{"type": "redirect", "responseStatusCode": "301"}},
+				{"URL", "https://www.example.com/", nil},
+			},
+			edges: []wantEdge{{"act_moved", "https://www.example.com/", "REDIRECT", nil}},
+		},
+		{
+			name:   "responder dynamic redirect kept as target",
+			lines:  []string{`add responder action act_https redirect "\"https://\" + HTTP.REQ.HOSTNAME + HTTP.REQ.URL.PATH_AND_QUERY" -responseStatusCode 302`},
+			parsed: true,
+			nodes: []wantNode{
+				{"ResponderAction", "act_https", map[string]string{"target": `"https://" + HTTP.REQ.HOSTNAME + HTTP.REQ.URL.PATH_AND_QUERY`}},
+			},
+			noNodes: []string{`"https://" + HTTP.REQ.HOSTNAME + HTTP.REQ.URL.PATH_AND_QUERY`, "https://"},
+		},
+		{
+			name:   "responder respondwithhtmlpage",
+			lines:  []string{"add responder action act_page respondwithhtmlpage page_maint -responseStatusCode 503"},
+			parsed: true,
+			nodes:  []wantNode{{"HTMLPage", "page_maint", nil}},
+			edges:  []wantEdge{{"act_page", "page_maint", "HTMLPAGE", nil}},
+		},
+		{
+			name:   "responder redirect without target",
+			lines:  []string{"add responder action act_bad redirect"},
+			parsed: true,
+			nodes:  []wantNode{{"ResponderAction", "act_bad", map[string]string{"type": "redirect"}}},
+		},
+		{
+			name:   "responder respondwith",
+			lines:  []string{`add responder action act_ok respondwith "\"HTTP/1.1 200 OK\r\n\r\n\""`},
+			parsed: true,
+			nodes:  []wantNode{{"ResponderAction", "act_ok", map[string]string{"target": `HTTP/1.1 200 OK\r\n\r\n`}}},
+		},
+		{
+			name:   "vpn global portal theme",
+			lines:  []string{"add vpn portaltheme theme1 -basetheme RfWebUI", "bind vpn global -portaltheme theme1"},
+			parsed: true,
+			nodes:  []wantNode{{"PortalTheme", "theme1", nil}},
+			edges:  []wantEdge{{"Global", "theme1", "", nil}},
+		},
+		{
+			name:   "vpn global sta server only",
+			lines:  []string{"bind vpn global -staServer \"http://sta01.corp.local\""},
+			parsed: true,
+			nodes:  []wantNode{{"STA", "http://sta01.corp.local", nil}},
+			edges:  []wantEdge{{"Global", "http://sta01.corp.local", "STA", nil}},
+		},
+		{
+			name:   "vpn global option fallback",
+			lines:  []string{"bind vpn global -sslCertkey cert_gw"},
+			parsed: true,
+			edges:  []wantEdge{{"Global", "cert_gw", "", nil}},
+		},
+		{
+			name:   "vpn global bookmark",
+			lines:  []string{"bind vpn global -urlName bm_intranet"},
+			parsed: true,
+			nodes:  []wantNode{{"VPNURL", "bm_intranet", nil}},
+			edges:  []wantEdge{{"Global", "bm_intranet", "BOOKMARK", nil}},
+		},
+		{
+			name:   "responder global positional",
+			lines:  []string{"add responder policy pol_deny true DROP", "bind responder global pol_deny 100 END -type REQ_DEFAULT"},
+			parsed: true,
+			edges:  []wantEdge{{"Global", "pol_deny", "REQ_DEFAULT", map[string]string{"priority": "100", "gotoPriorityExpression": "END"}}},
+		},
+		{
+			name:   "vserver invoke from bind point",
+			lines:  []string{"bind lb vserver lb1 -policyName pol_rw -priority 100 -gotoPriorityExpression NEXT -type REQUEST -invoke policylabel pl_rw"},
+			parsed: true,
+			nodes:  []wantNode{{"PolicyLabel", "pl_rw", nil}},
+			edges: []wantEdge{
+				{"lb1", "pol_rw", "", map[string]string{"invoke": "policylabel pl_rw"}},
+				{"lb1", "pl_rw", "INVOKE", map[string]string{"policy": "pol_rw", "priority": "100"}},
+			},
+			noEdges: [][2]string{{"pol_rw", "pl_rw"}},
+		},
+		{
+			name:    "policy label invoke from bind point",
+			lines:   []string{"bind rewrite policylabel pl_outer pol_rw 10 NEXT -invoke policylabel pl_inner"},
+			parsed:  true,
+			edges:   []wantEdge{{"pl_outer", "pl_inner", "INVOKE", map[string]string{"policy": "pol_rw"}}},
+			noEdges: [][2]string{{"pol_rw", "pl_inner"}},
+		},
+		{
+			name:    "global invoke from bind point",
+			lines:   []string{"bind responder global pol_all 100 NEXT -type REQ_DEFAULT -invoke vserver cs_app"},
+			parsed:  true,
+			edges:   []wantEdge{{"Global", "cs_app", "INVOKE", map[string]string{"policy": "pol_all"}}},
+			noEdges: [][2]string{{"pol_all", "cs_app"}},
+		},
+		{
+			name:   "truncated cmp action",
+			lines:  []string{"add cmp action cmp_act"},
+			parsed: false,
+		},
+		{
+			name:   "truncated feo policy",
+			lines:  []string{"add feo policy feo_pol true"},
+			parsed: false,
+		},
+		{
+			name:   "truncated transform policy",
+			lines:  []string{"add transform policy tf_pol true"},
+			parsed: false,
+		},
+		{
+			name:   "transform action without priority",
+			lines:  []string{"add transform action tf_act tf_prof"},
+			parsed: true,
+			nodes:  []wantNode{{"TransformAction", "tf_act", nil}, {"TransformProfile", "tf_prof", nil}},
+			edges:  []wantEdge{{"tf_prof", "tf_act", "", nil}},
+		},
+		{
+			name:   "transform action with priority",
+			lines:  []string{"add transform action tf_act tf_prof 10"},
+			parsed: true,
+			edges:  []wantEdge{{"tf_prof", "tf_act", "", map[string]string{"priority": "10"}}},
+		},
+		{
+			name:   "truncated authorization policy",
+			lines:  []string{"add authorization policy authz_pol true"},
+			parsed: false,
+		},
+		{
+			name:   "truncated tm session policy",
+			lines:  []string{"add tm sessionPolicy tm_pol true"},
+			parsed: false,
+		},
+		{
+			name:   "truncated vpn traffic policy",
+			lines:  []string{"add vpn trafficPolicy tp_pol true"},
+			parsed: false,
+		},
+		{
+			name:   "vpn traffic action without qualifier",
+			lines:  []string{"add vpn trafficAction ta_sso"},
+			parsed: true,
+			nodes:  []wantNode{{"TrafficAction", "ta_sso", map[string]string{"feature": "vpn", "qual": ""}}},
+		},
+		{
+			name:   "vpn traffic action with qualifier",
+			lines:  []string{"add vpn trafficAction ta_kcd http -SSO ON -kcdAccount kcd_svc"},
+			parsed: true,
+			nodes:  []wantNode{{"TrafficAction", "ta_kcd", map[string]string{"qual": "http", "kcdAccount": "kcd_svc"}}},
+		},
+		{
+			name:   "vpn intranet application ip and netmask",
+			lines:  []string{"add vpn intranetApplication ia_net TCP 10.1.0.0 -netmask 255.255.0.0 -destPort 1-65535 -interception TRANSPARENT"},
+			parsed: true,
+			nodes: []wantNode{{"IntranetApp", "ia_net", map[string]string{
+				"destination": "10.1.0.0", "netmask": "255.255.0.0", "destPort": "1-65535", "protocol": "TCP", "interception": "TRANSPARENT",
+			}}},
+			noNodes: []string{"10.1.0.0"},
+		},
+		{
+			name:   "vpn intranet application single ip",
+			lines:  []string{"add vpn intranetApplication ia_srv TCP 10.1.0.5 -destPort 3389"},
+			parsed: true,
+			nodes:  []wantNode{{"Server", "10.1.0.5", nil}},
+			edges:  []wantEdge{{"ia_srv", "10.1.0.5", "TCP", nil}},
+		},
+		{
+			name:    "vpn intranet application wildcard hostname",
+			lines:   []string{`add vpn intranetApplication ia_wild ANY -hostName "*.corp.local" -interception TRANSPARENT`},
+			parsed:  true,
+			nodes:   []wantNode{{"IntranetApp", "ia_wild", map[string]string{"destination": "*.corp.local", "hostName": "*.corp.local", "protocol": "ANY"}}},
+			noNodes: []string{"-hostName", "*.corp.local"},
+		},
+		{
+			name:    "vpn intranet application hostname",
+			lines:   []string{"add vpn intranetApplication ia_host TCP -hostName intranet.corp.local -destPort 443"},
+			parsed:  true,
+			nodes:   []wantNode{{"Server", "intranet.corp.local", nil}},
+			edges:   []wantEdge{{"ia_host", "intranet.corp.local", "TCP", nil}},
+			noNodes: []string{"-hostName"},
+		},
+		{
+			name:   "vpn intranet application ip range",
+			lines:  []string{"add vpn intranetApplication ia_range TCP -iprange 10.2.0.10-10.2.0.50 -destPort 22"},
+			parsed: true,
+			nodes: []wantNode{{"IntranetApp", "ia_range", map[string]string{
+				"destination": "10.2.0.10-10.2.0.50", "iprange": "10.2.0.10-10.2.0.50", "destPort": "22",
+			}}},
+			noNodes: []string{"-iprange"},
+		},
+		{
+			name:   "truncated vpn intranet application",
+			lines:  []string{"add vpn intranetApplication ia_bad"},
+			parsed: false,
+		},
+		{
+			name:   "ssl vserver cert",
+			lines:  []string{"bind ssl vserver lb_app -certkeyName cert_app"},
+			parsed: true,
+			nodes:  []wantNode{{"Cert", "cert_app", nil}},
+			edges:  []wantEdge{{"lb_app", "cert_app", "CERT", nil}},
+		},
+		{
+			name:   "ssl vserver ca cert with ocsp",
+			lines:  []string{"bind ssl vserver vpn_gw -certkeyName ca_corp -CA -ocspCheck Mandatory"},
+			parsed: true,
+			nodes:  []wantNode{{"CACert", "ca_corp", nil}},
+			edges:  []wantEdge{{"vpn_gw", "ca_corp", "CACERT", map[string]string{"ocspCheck": "Mandatory"}}},
+		},
+		{
+			name:   "ssl vserver sni cert",
+			lines:  []string{"bind ssl vserver cs_front -certkeyName cert_www -SNICert"},
+			parsed: true,
+			edges:  []wantEdge{{"cs_front", "cert_www", "SNICERT", nil}},
+		},
+		{
+			name:   "ssl service cipher group",
+			lines:  []string{"bind ssl service svc_ssl -cipherName cg_strong"},
+			parsed: true,
+			nodes:  []wantNode{{"CipherGroup", "cg_strong", nil}},
+			edges:  []wantEdge{{"svc_ssl", "cg_strong", "CIPHER", nil}},
+		},
+		{
+			name:   "ssl vserver ecc curve only",
+			lines:  []string{"bind ssl vserver lb_app -eccCurveName P_256"},
+			parsed: false,
+		},
+		{
+			name:   "ssl cert chain upgrades to ca cert",
+			lines:  []string{"add ssl certkey cert_app -cert app.pem", "add ssl certkey ca_int -cert int.pem", "link ssl certkey cert_app ca_int"},
+			parsed: true,
+			nodes:  []wantNode{{"Cert", "cert_app", nil}, {"CACert", "ca_int", map[string]string{"certFile": "int.pem"}}},
+			edges:  []wantEdge{{"cert_app", "ca_int", "CHAIN", nil}},
+		},
+		{
+			name:   "ssl vserver profile",
+			lines:  []string{"add ssl profile prof_fe -sslProfileType FrontEnd -tls13 ENABLED", "set ssl vserver lb_app -sslProfile prof_fe"},
+			parsed: true,
+			nodes:  []wantNode{{"SSLProfile", "prof_fe", map[string]string{"tls13": "ENABLED"}}},
+			edges:  []wantEdge{{"lb_app", "prof_fe", "SSLPROFILE", nil}},
+		},
+		{
+			name:   "lb monitor",
+			lines:  []string{`add lb monitor mon_http HTTP-ECV -send "GET /health" -recv OK -interval 10 -destPort 8080 -secure YES`},
+			parsed: true,
+			nodes: []wantNode{{"Monitor", "mon_http", map[string]string{
+				"type": "HTTP-ECV", "send": "GET /health", "recv": "OK", "interval": "10", "destPort": "8080", "secure": "YES",
+			}}},
+		},
+		{
+			name:   "truncated lb monitor",
+			lines:  []string{"add lb monitor mon_bad"},
+			parsed: false,
+		},
+		{
+			name:   "service monitor",
+			lines:  []string{"add service svc1 10.0.0.10 HTTP 80", "bind service svc1 -monitorName mon_http"},
+			parsed: true,
+			nodes:  []wantNode{{"Monitor", "mon_http", nil}},
+			edges:  []wantEdge{{"svc1", "mon_http", "MONITOR", nil}},
+		},
+		{
+			name:   "service group monitor",
+			lines:  []string{"add serviceGroup sg_web HTTP", "bind serviceGroup sg_web -monitorName mon_tcp"},
+			parsed: true,
+			nodes:  []wantNode{{"Monitor", "mon_tcp", nil}},
+			edges:  []wantEdge{{"sg_web", "mon_tcp", "MONITOR", nil}},
+		},
+		{
+			name:   "service group member",
+			lines:  []string{"add serviceGroup sg_web HTTP", "bind serviceGroup sg_web srv1 8080"},
+			parsed: true,
+			edges:  []wantEdge{{"sg_web", "srv1", "", nil}},
+		},
+		{
+			name:   "lb monitor bound to service",
+			lines:  []string{"bind lb monitor mon_ping svc1"},
+			parsed: true,
+			nodes:  []wantNode{{"Monitor", "mon_ping", nil}},
+			edges:  []wantEdge{{"svc1", "mon_ping", "MONITOR", nil}},
+		},
+		{
+			name:   "appfw policy and profile",
+			lines:  []string{"add appfw profile prof_waf -defaults advanced -type HTML XML", "add appfw policy pol_waf true prof_waf"},
+			parsed: true,
+			nodes: []wantNode{
+				{"AppFWPolicy", "pol_waf", nil},
+				{"AppFWProfile", "prof_waf", map[string]string{"defaults": "advanced", "type": "HTML"}},
+			},
+			edges: []wantEdge{{"pol_waf", "prof_waf", "", nil}},
+		},
+		{
+			name:   "appfw profile relaxations counted",
+			lines:  []string{"add appfw profile prof_waf", `bind appfw profile prof_waf -startURL "^https://app/"`, `bind appfw profile prof_waf -startURL "^https://app/login"`, "bind appfw profile prof_waf -denyURL debug"},
+			parsed: true,
+			nodes:  []wantNode{{"AppFWProfile", "prof_waf", map[string]string{"startURL": "2", "denyURL": "1"}}},
+		},
+		{
+			name:   "appfw profile bind without option",
+			lines:  []string{"bind appfw profile prof_waf something"},
+			parsed: false,
+		},
+		{
+			name:   "bot policy and profile",
+			lines:  []string{"add bot profile bot_prof -signature bot_sig", "add bot policy pol_bot -rule true -profileName bot_prof"},
+			parsed: true,
+			nodes:  []wantNode{{"BotPolicy", "pol_bot", nil}, {"BotProfile", "bot_prof", nil}},
+			edges:  []wantEdge{{"pol_bot", "bot_prof", "", nil}},
+		},
+		{
+			name:   "bot profile rules counted",
+			lines:  []string{"add bot profile bot_prof", "bind bot profile bot_prof -blacklist -type IPv4 -value 192.0.2.1"},
+			parsed: true,
+			nodes:  []wantNode{{"BotProfile", "bot_prof", map[string]string{"blacklist": "1"}}},
+		},
+		{
+			name:   "bot policy bound globally keeps its type",
+			lines:  []string{"add bot policy pol_bot -rule true -profileName bot_prof", "bind bot global -policyName pol_bot -priority 10 -type REQ_DEFAULT"},
+			parsed: true,
+			nodes:  []wantNode{{"BotPolicy", "pol_bot", nil}},
+			edges:  []wantEdge{{"Global", "pol_bot", "REQ_DEFAULT", map[string]string{"priority": "10", "feature": "bot"}}},
+		},
+		{
+			name:   "ica access profile",
+			lines:  []string{"add ica accessprofile icap_locked -ConnectClientLPTPorts DISABLED -ClientClipboardRedirection DISABLED"},
+			parsed: true,
+			nodes:  []wantNode{{"ICAAccessProfile", "icap_locked", map[string]string{"ClientClipboardRedirection": "DISABLED", "ConnectClientLPTPorts": "DISABLED"}}},
+		},
+		{
+			name:   "ica latency profile",
+			lines:  []string{"add ica latencyprofile ical_mon -l7LatencyMonitoring ENABLED -l7LatencyThresholdFactor 4"},
+			parsed: true,
+			nodes:  []wantNode{{"ICALatencyProfile", "ical_mon", map[string]string{"l7LatencyMonitoring": "ENABLED", "l7LatencyThresholdFactor": "4"}}},
+		},
+		{
+			name:   "ica action and policy",
+			lines:  []string{"add ica action ica_act -accessProfileName icap_locked -latencyProfileName ical_mon", "add ica policy ica_pol -rule true -action ica_act"},
+			parsed: true,
+			nodes: []wantNode{
+				{"ICAPolicy", "ica_pol", nil},
+				{"ICAAction", "ica_act", nil},
+				{"ICAAccessProfile", "icap_locked", nil},
+				{"ICALatencyProfile", "ical_mon", nil},
+			},
+			edges: []wantEdge{
+				{"ica_pol", "ica_act", "", nil},
+				{"ica_act", "icap_locked", "", nil},
+				{"ica_act", "ical_mon", "", nil},
+			},
+		},
+		{
+			name:   "vpn vserver ica profile",
+			lines:  []string{"bind vpn vserver vpn_gw -icaProfile icap_locked"},
+			parsed: true,
+			nodes:  []wantNode{{"ICAAccessProfile", "icap_locked", nil}},
+			edges:  []wantEdge{{"vpn_gw", "icap_locked", "ICA", nil}},
+		},
+		{
+			name:   "vpn parameter",
+			lines:  []string{`set vpn parameter -icaProxy ON -storefronturl "https://sf.corp.local" -clientlessVpnMode OFF`},
+			parsed: true,
+			nodes:  []wantNode{{"VIP", "Global", map[string]string{"icaProxy": "ON", "clientlessVpnMode": "OFF"}}, {"WI", "https://sf.corp.local", nil}},
+			edges:  []wantEdge{{"Global", "https://sf.corp.local", "STOREFRONT", nil}},
+		},
+		{
+			name:   "vpn parameter without session settings",
+			lines:  []string{"set vpn parameter -forceCleanup none"},
+			parsed: false,
+		},
+		{
+			name:   "stringmap bind",
+			lines:  []string{"add policy stringmap sm_hosts", `bind policy stringmap sm_hosts www.example.com "pool_a"`},
+			parsed: true,
+			nodes:  []wantNode{{"StringMap", "sm_hosts", map[string]string{"entries": "www.example.com=pool_a"}}},
+		},
+		{
+			name:   "global bind with nothing to draw",
+			lines:  []string{"bind lb global "},
+			parsed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last := tt.lines[len(tt.lines)-1]
+			ns := parseLines(t, tt.lines[:len(tt.lines)-1]...)
+			ns.lineNum++
+			parsed := ns.parseNSline(last)
+			if parsed != tt.parsed {
+				t.Errorf("parseNSline(%q) = %v, want %v", last, parsed, tt.parsed)
+			}
+			for _, want := range tt.nodes {
+				n := findNode(ns, want.name)
+				if n == nil {
+					t.Errorf("missing node %q", want.name)
+					continue
+				}
+				if n.nstype != want.nstype {
+					t.Errorf("node %q type = %q, want %q", want.name, n.nstype, want.nstype)
+				}
+				for k, v := range want.attrs {
+					if n.attrs[k] != v {
+						t.Errorf("node %q attr %s = %q, want %q", want.name, k, n.attrs[k], v)
+					}
+				}
+			}
+			for _, want := range tt.edges {
+				e := findEdge(ns, want.from, want.to)
+				if e == nil {
+					t.Errorf("missing edge %q -> %q", want.from, want.to)
+					continue
+				}
+				if want.protocol != "" && e.protocol != want.protocol {
+					t.Errorf("edge %q -> %q protocol = %q, want %q", want.from, want.to, e.protocol, want.protocol)
+				}
+				for k, v := range want.attrs {
+					if e.attrs[k] != v {
+						t.Errorf("edge %q -> %q attr %s = %q, want %q", want.from, want.to, k, e.attrs[k], v)
+					}
+				}
+			}
+			for _, name := range tt.noNodes {
+				if findNode(ns, name) != nil {
+					t.Errorf("unexpected node %q", name)
+				}
+			}
+			for _, e := range tt.noEdges {
+				if findEdge(ns, e[0], e[1]) != nil {
+					t.Errorf("unexpected edge %q -> %q", e[0], e[1])
+				}
+			}
+		})
+	}
+}
+
+func TestParseLDAPAction(t *testing.T) {
+	tests := []struct {
+		line                string
+		server, port, proto string
+		attrs               map[string]string
+	}{
+		{
+			line:   "add authentication ldapAction ldap_plain -serverIP 10.0.0.5",
+			server: "10.0.0.5", port: "389", proto: "LDAP",
+		},
+		{
+			line:   "add authentication ldapAction ldap_ssl -serverIP 10.0.0.5 -serverPort 636 -secType SSL",
+			server: "10.0.0.5", port: "636", proto: "LDAPS",
+			attrs: map[string]string{"secType": "SSL"},
+		},
+		{
+			line:   "add authentication ldapAction ldap_tls -serverIP 10.0.0.5 -secType TLS",
+			server: "10.0.0.5", port: "389", proto: "LDAP_TLS",
+		},
+		{
+			line:   "add authentication ldapAction ldap_636 -serverIP 10.0.0.5 -serverPort 636",
+			server: "10.0.0.5", port: "636", proto: "LDAPS",
+		},
+		{
+			line:   `add authentication ldapAction ldap_dc -serverName dc01.corp.local -serverPort 3269 -secType SSL -ldapBase "dc=corp,dc=local" -ldapBindDn svc_ldap@corp.local -ldapLoginName sAMAccountName`,
+			server: "dc01.corp.local", port: "3269", proto: "LDAPS",
+			attrs: map[string]string{
+				"type":          "ldapAction",
+				"ldapBase":      "dc=corp,dc=local",
+				"ldapBindDn":    "svc_ldap@corp.local",
+				"ldapLoginName": "sAMAccountName",
+			},
+		},
+	}
+	for _, tt := range tests {
+		ns := parseLines(t, tt.line)
+		name := terms(tt.line)[3]
+		n := findNode(ns, name)
+		if n == nil || n.nstype != "AuthAction" {
+			t.Errorf("%s: node = %+v, want AuthAction", name, n)
+			continue
+		}
+		for k, v := range tt.attrs {
+			if n.attrs[k] != v {
+				t.Errorf("%s: attr %s = %q, want %q", name, k, n.attrs[k], v)
+			}
+		}
+		if s := findNode(ns, tt.server); s == nil || s.nstype != "Server" && s.nstype != "Unknown" {
+			t.Errorf("%s: server node = %+v", name, s)
+		}
+		e := findEdge(ns, name, tt.server)
+		if e == nil {
+			t.Errorf("%s: missing edge to %q", name, tt.server)
+			continue
+		}
+		if e.port != tt.port || e.protocol != tt.proto {
+			t.Errorf("%s: edge = %s/%s, want %s/%s", name, e.port, e.protocol, tt.port, tt.proto)
+		}
+	}
+}
+
+func TestIgnoreMonitors(t *testing.T) {
+	config := `add service svc1 10.0.0.10 HTTP 80
+add lb monitor mon_http HTTP
+bind service svc1 -monitorName mon_http
+`
+	if n := findNode(parseConfig(t, config), "mon_http"); n == nil {
+		t.Error("monitor missing when not ignored")
+	}
+
+	path := filepath.Join(t.TempDir(), "ns.conf")
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ns := New("LR", []string{}, []string{"Monitor"}, []string{})
+	if err := ns.Parse(path); err != nil {
+		t.Fatal(err)
+	}
+	if n := findNode(ns, "mon_http"); n != nil {
+		t.Error("monitor kept when its type is ignored")
+	}
+	// edges to ignored nodes are dropped on export
+	out := filepath.Join(t.TempDir(), "out.dot")
+	ns.ExportDot(out, false)
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "mon_http") {
+		t.Errorf("ignored monitor drawn:\n%s", b)
+	}
+}
+
+func TestParseAuthActions(t *testing.T) {
+	type wantServer struct{ to, port, protocol string }
+	tests := []struct {
+		line    string
+		attrs   map[string]string
+		servers []wantServer
+	}{
+		{
+			line:    "add authentication tacacsAction tac1 -serverIP 10.0.0.7 -tacacsSecret secret",
+			attrs:   map[string]string{"type": "tacacsAction"},
+			servers: []wantServer{{"10.0.0.7", "49", "TACACS"}},
+		},
+		{
+			line:  "add authentication certAction cert1 -twoFactor ON -userNameField Subject:CN",
+			attrs: map[string]string{"type": "certAction", "twoFactor": "ON", "userNameField": "Subject:CN"},
+		},
+		{
+			line:    `add authentication negotiateAction neg1 -domain CORP.LOCAL -domainUser svc_kerb -NTLMPath "http://ntlm.corp.local/auth"`,
+			attrs:   map[string]string{"type": "negotiateAction", "domainUser": "svc_kerb"},
+			servers: []wantServer{{"CORP.LOCAL", "88", "KERBEROS"}, {"ntlm.corp.local", "", "NTLM"}},
+		},
+		{
+			line:    "add authentication webAuthAction web1 -serverIP 10.0.0.8 -serverPort 443 -scheme https",
+			attrs:   map[string]string{"type": "webAuthAction"},
+			servers: []wantServer{{"10.0.0.8", "443", "HTTPS"}},
+		},
+		{
+			line:    `add authentication emailAction email1 -userName otp -serverURL "smtps://mail.corp.local:465" -type SMTP`,
+			attrs:   map[string]string{"type": "emailAction"},
+			servers: []wantServer{{"mail.corp.local", "465", "SMTP"}},
+		},
+		{
+			line:    `add authentication emailAction email2 -serverURL "https://mail.corp.local/ews" -type ATHENA`,
+			servers: []wantServer{{"mail.corp.local", "", "ATHENA"}},
+		},
+		{
+			line:    `add authentication epaAction epa1 -csecexpr "sys.client_expr(\"proc_0_0\")" -defaultEPAGroup grp_ok -quarantineGroup grp_quarantine`,
+			attrs:   map[string]string{"type": "epaAction", "csecexpr": `sys.client_expr("proc_0_0")`},
+			servers: []wantServer{{"grp_ok", "", "DEFAULTEPAGROUP"}, {"grp_quarantine", "", "QUARANTINEGROUP"}},
+		},
+		{
+			line:  "add authentication noAuthAction noauth1",
+			attrs: map[string]string{"type": "noAuthAction"},
+		},
+		{
+			line:    `add authentication dfaAction dfa1 -clientID nsdfa -serverURL "https://dfa.corp.local:8443/" -passphrase secret`,
+			attrs:   map[string]string{"type": "dfaAction"},
+			servers: []wantServer{{"dfa.corp.local", "8443", "DFA"}},
+		},
+		{
+			line:    `add authentication captchaAction cap1 -serverURL "https://www.google.com/recaptcha/api/siteverify" -secretKey s -siteKey k`,
+			attrs:   map[string]string{"type": "captchaAction"},
+			servers: []wantServer{{"www.google.com", "", "CAPTCHA"}},
+		},
+		{
+			line:    `add authentication storefrontAuthAction sf1 -serverURL "https://sf.corp.local/Citrix/Store" -domain CORP`,
+			attrs:   map[string]string{"type": "storefrontAuthAction"},
+			servers: []wantServer{{"sf.corp.local", "", "STOREFRONT"}},
+		},
+		{
+			line:    `add authentication samlIdPProfile idp1 -samlIdPCertName cert_idp -samlSPCertName cert_sp -assertionConsumerServiceURL "https://sp.example.com/saml/acs"`,
+			attrs:   map[string]string{"type": "samlIdPProfile"},
+			servers: []wantServer{{"cert_idp", "", "CERT"}, {"cert_sp", "", "CERT"}, {"sp.example.com", "", "SAML"}},
+		},
+	}
+	for _, tt := range tests {
+		ns := parseLines(t, tt.line)
+		name := terms(tt.line)[3]
+		n := findNode(ns, name)
+		if n == nil || n.nstype != "AuthAction" {
+			t.Errorf("%s: node = %+v, want AuthAction", name, n)
+			continue
+		}
+		for k, v := range tt.attrs {
+			if n.attrs[k] != v {
+				t.Errorf("%s: attr %s = %q, want %q", name, k, n.attrs[k], v)
+			}
+		}
+		edges := 0
+		for _, e := range ns.Edges {
+			if e.from == name {
+				edges++
+			}
+		}
+		if edges != len(tt.servers) {
+			t.Errorf("%s: %d edges, want %d", name, edges, len(tt.servers))
+		}
+		for _, want := range tt.servers {
+			e := findEdge(ns, name, want.to)
+			if e == nil {
+				t.Errorf("%s: missing edge to %q", name, want.to)
+				continue
+			}
+			if e.port != want.port || e.protocol != want.protocol {
+				t.Errorf("%s: edge to %q = %s/%s, want %s/%s", name, want.to, e.port, e.protocol, want.port, want.protocol)
+			}
+		}
+	}
+}
+
+func TestParseTruncatedLines(t *testing.T) {
+	lines := []string{
+		"add appfw policy pol_waf true",
+		"add cs policylabel pl_cs",
+		"add dns addRec www.example.com",
+		"add dns cnameRec www.example.com",
+		"add gslb serviceGroup gsg_app",
+		"add policy dataset ds_ids",
+		"add policy expression exp_host",
+		"add responder action act_none",
+		"add rewrite action act_none",
+		"add rewrite policylabel pl_rw",
+		"add vpn clientlessAccessPolicy cap_pol true",
+		"add vpn url bm_intranet Intranet",
+		"import responder htmlpage page.html",
+		"bind cs policylabel pl_cs",
+		"bind lb monitor mon_http",
+		"bind policy stringmap sm_hosts www.example.com",
+	}
+	for _, line := range lines {
+		ns := parseLines(t)
+		if ns.parseNSline(line) {
+			t.Errorf("parseNSline(%q) = true, want false", line)
+		}
+	}
+}
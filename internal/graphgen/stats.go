@@ -0,0 +1,164 @@
+package graphgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// statsDegreeLimit is the number of nodes listed for fan-in and fan-out.
+var statsDegreeLimit = 5
+
+// Stats summarises the size and shape of a parsed config.
+type Stats struct {
+	Nodes            int            `json:"nodes"`
+	Edges            int            `json:"edges"`
+	Types            map[string]int `json:"types"`
+	Protocols        map[string]int `json:"protocols"`
+	VIPs             map[string]int `json:"vips"`
+	FanIn            []StatsDegree  `json:"fanIn"`
+	FanOut           []StatsDegree  `json:"fanOut"`
+	Unparsed         map[string]int `json:"unparsed"`
+	UnparsedLines    int            `json:"unparsedLines"`
+	Components       int            `json:"components"`
+	LargestComponent int            `json:"largestComponent"`
+}
+
+// StatsDegree is the number of edges into or out of a single node.
+type StatsDegree struct {
+	NSType string `json:"nstype"`
+	Name   string `json:"name"`
+	Edges  int    `json:"edges"`
+}
+
+// Stats counts nodes, edges, protocols and connected components in the graph.
+func (ns *NSGraph) Stats() Stats {
+	st := Stats{
+		Nodes:     len(ns.Nodes),
+		Edges:     len(ns.Edges),
+		Types:     map[string]int{},
+		Protocols: map[string]int{},
+		VIPs:      map[string]int{},
+		Unparsed:  ns.unparsed,
+	}
+
+	in := map[string]int{}
+	out := map[string]int{}
+	for _, e := range ns.Edges {
+		out[e.from]++
+		in[e.to]++
+	}
+
+	for _, n := range ns.Nodes {
+		st.Types[n.nstype]++
+		if n.protocol != "" && (strings.HasSuffix(n.nstype, "VServer") || strings.HasPrefix(n.nstype, "Service") || n.nstype == "GSLBService") {
+			st.Protocols[n.protocol]++
+		}
+		if n.nstype == "VIP" {
+			st.VIPs[n.label] = out[n.label]
+		}
+		st.FanIn = append(st.FanIn, StatsDegree{NSType: n.nstype, Name: n.label, Edges: in[n.label]})
+		st.FanOut = append(st.FanOut, StatsDegree{NSType: n.nstype, Name: n.label, Edges: out[n.label]})
+	}
+	st.FanIn = topDegrees(st.FanIn)
+	st.FanOut = topDegrees(st.FanOut)
+
+	for _, c := range ns.unparsed {
+		st.UnparsedLines += c
+	}
+
+	// weakly connected components, by union-find over node labels
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(x string) string {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	for _, n := range ns.Nodes {
+		parent[n.label] = n.label
+	}
+	for _, e := range ns.Edges {
+		if _, ok := parent[e.from]; !ok {
+			continue
+		}
+		if _, ok := parent[e.to]; !ok {
+			continue
+		}
+		parent[find(e.from)] = find(e.to)
+	}
+	sizes := map[string]int{}
+	for label := range parent {
+		sizes[find(label)]++
+	}
+	st.Components = len(sizes)
+	for _, size := range sizes {
+		st.LargestComponent = max(st.LargestComponent, size)
+	}
+
+	return st
+}
+
+func topDegrees(degrees []StatsDegree) []StatsDegree {
+	slices.SortStableFunc(degrees, func(a, b StatsDegree) int {
+		if a.Edges != b.Edges {
+			return b.Edges - a.Edges
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	top := []StatsDegree{}
+	for _, d := range degrees {
+		if len(top) >= statsDegreeLimit || d.Edges < 1 {
+			break
+		}
+		top = append(top, d)
+	}
+	return top
+}
+
+func writeCounts(sb *strings.Builder, title string, counts map[string]int) {
+	fmt.Fprintf(sb, "\n%s\n", title)
+	keys := sortedKeys(counts)
+	slices.SortStableFunc(keys, func(a, b string) int { return counts[b] - counts[a] })
+	for _, k := range keys {
+		fmt.Fprintf(sb, "  %-40s %6d\n", k, counts[k])
+	}
+}
+
+// WriteStatsTable writes stats as plain text tables. The tables are built in
+// memory first, so a failed write is reported once rather than per line.
+func WriteStatsTable(w io.Writer, st Stats) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-42s %6d\n", "Nodes", st.Nodes)
+	fmt.Fprintf(&b, "%-42s %6d\n", "Edges", st.Edges)
+	fmt.Fprintf(&b, "%-42s %6d\n", "Connected components", st.Components)
+	fmt.Fprintf(&b, "%-42s %6d\n", "Largest component", st.LargestComponent)
+	fmt.Fprintf(&b, "%-42s %6d\n", "Unparsed lines", st.UnparsedLines)
+
+	writeCounts(&b, "Types", st.Types)
+	writeCounts(&b, "Protocols", st.Protocols)
+	writeCounts(&b, "VIPs (outbound edges)", st.VIPs)
+
+	fmt.Fprintf(&b, "\nFan-in\n")
+	for _, d := range st.FanIn {
+		fmt.Fprintf(&b, "  %-40s %6d  %s\n", d.Name, d.Edges, d.NSType)
+	}
+	fmt.Fprintf(&b, "\nFan-out\n")
+	for _, d := range st.FanOut {
+		fmt.Fprintf(&b, "  %-40s %6d  %s\n", d.Name, d.Edges, d.NSType)
+	}
+
+	writeCounts(&b, "Unparsed lines by command", st.Unparsed)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteStatsJSON writes stats as a JSON object.
+func WriteStatsJSON(w io.Writer, st Stats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(st)
+}
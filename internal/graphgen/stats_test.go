@@ -0,0 +1,32 @@
+package graphgen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteStatsTable(t *testing.T) {
+	ns := parseConfig(t, orphanConfig)
+	st := ns.Stats()
+
+	var b strings.Builder
+	if err := WriteStatsTable(&b, st); err != nil {
+		t.Fatalf("WriteStatsTable() error = %v", err)
+	}
+	for _, want := range []string{"Nodes", "Types", "Fan-out", "Unparsed lines by command"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteStatsTable() output missing %q", want)
+		}
+	}
+
+	if err := WriteStatsTable(failingWriter{}, st); err == nil {
+		t.Error("WriteStatsTable() to a failing writer returned nil error")
+	}
+}